@@ -0,0 +1,214 @@
+package svg
+
+import "math"
+
+// Arc is the center parameterization of an elliptical arc.
+type Arc struct {
+	Center Point
+	RX, RY float64
+
+	// Rotation is the angle from the x-axis of the coordinate system to the
+	// x-axis of the ellipse in radians.
+	Rotation float64
+
+	// Start is the angle of the start point of the arc and Sweep is the angle
+	// the arc extends over, both in radians. Sweep is positive if the arc is
+	// drawn in the direction of increasing angles.
+	Start, Sweep float64
+}
+
+// ArcCenter converts an elliptical arc from the endpoint parameterization used
+// in path data to its center parameterization. The arc is drawn from the point
+// from to the point to and rotation is given in degrees, as in the 'A'
+// command. Radii that are too small to reach the end point are scaled up.
+// Returns false if the arc is drawn as a straight line or omitted, which is
+// the case when a radius is zero or the end points are the same.
+func ArcCenter(from, to Point, rx, ry, rotation float64,
+	largeArc, sweep bool) (Arc, bool) {
+
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 || from == to {
+		return Arc{}, false
+	}
+
+	phi := rotation * math.Pi / 180
+	sin, cos := math.Sincos(phi)
+
+	// Step 1: compute the start point in the coordinate system of the
+	// ellipse, translated to the midpoint between the end points.
+	dx, dy := (from.X-to.X)/2, (from.Y-to.Y)/2
+	x1 := cos*dx + sin*dy
+	y1 := -sin*dx + cos*dy
+
+	// Correct out-of-range radii.
+	if lambda := x1*x1/(rx*rx) + y1*y1/(ry*ry); lambda > 1 {
+		rx *= math.Sqrt(lambda)
+		ry *= math.Sqrt(lambda)
+	}
+
+	// Step 2: compute the transformed center.
+	numerator := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	denominator := rx*rx*y1*y1 + ry*ry*x1*x1
+	coefficient := math.Sqrt(math.Max(0, numerator/denominator))
+	if largeArc == sweep {
+		coefficient = -coefficient
+	}
+	cx1 := coefficient * rx * y1 / ry
+	cy1 := -coefficient * ry * x1 / rx
+
+	// Step 3: compute the center in the original coordinate system.
+	center := Point{
+		X: cos*cx1 - sin*cy1 + (from.X+to.X)/2,
+		Y: sin*cx1 + cos*cy1 + (from.Y+to.Y)/2,
+	}
+
+	// Step 4: compute the start and sweep angles.
+	start := angle(1, 0, (x1-cx1)/rx, (y1-cy1)/ry)
+	delta := angle((x1-cx1)/rx, (y1-cy1)/ry, (-x1-cx1)/rx, (-y1-cy1)/ry)
+
+	if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	} else if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	}
+
+	return Arc{
+		Center:   center,
+		RX:       rx,
+		RY:       ry,
+		Rotation: phi,
+		Start:    start,
+		Sweep:    delta,
+	}, true
+}
+
+// Point returns the point on the ellipse of the arc at the given angle.
+func (a Arc) Point(theta float64) Point {
+	sin, cos := math.Sincos(a.Rotation)
+	x, y := a.RX*math.Cos(theta), a.RY*math.Sin(theta)
+
+	return Point{
+		X: a.Center.X + cos*x - sin*y,
+		Y: a.Center.Y + sin*x + cos*y,
+	}
+}
+
+// derivative returns the derivative of the ellipse of the arc with respect to
+// the angle.
+func (a Arc) derivative(theta float64) Point {
+	sin, cos := math.Sincos(a.Rotation)
+	x, y := -a.RX*math.Sin(theta), a.RY*math.Cos(theta)
+
+	return Point{cos*x - sin*y, sin*x + cos*y}
+}
+
+// maxArcSegments limits the number of curves a single arc is split into.
+const maxArcSegments = 1024
+
+// Cubics approximates the arc with absolute cubic Bézier curves that deviate
+// from it by no more than tolerance. Each curve spans at most a quarter of the
+// ellipse; a non-positive tolerance does not split the arc any further.
+func (a Arc) Cubics(tolerance float64) []*PathCommand {
+	sweep := math.Abs(a.Sweep)
+	radius := math.Max(a.RX, a.RY)
+
+	count := int(math.Ceil(sweep / (math.Pi / 2)))
+	if count == 0 {
+		count = 1
+	}
+
+	for tolerance > 0 && count < maxArcSegments &&
+		arcError(radius, sweep/float64(count)) > tolerance {
+		count++
+	}
+
+	step := a.Sweep / float64(count)
+	k := 4.0 / 3 * math.Tan(step/4)
+
+	var commands []*PathCommand
+	for i := 0; i < count; i++ {
+		theta1 := a.Start + step*float64(i)
+		theta2 := theta1 + step
+
+		p1, p2 := a.Point(theta1), a.Point(theta2)
+		d1, d2 := a.derivative(theta1), a.derivative(theta2)
+
+		commands = append(commands, &PathCommand{
+			Symbol: "C",
+			Params: []float64{
+				p1.X + k*d1.X, p1.Y + k*d1.Y,
+				p2.X - k*d2.X, p2.Y - k*d2.Y,
+				p2.X, p2.Y,
+			},
+		})
+	}
+
+	return commands
+}
+
+// ArcsToCubics returns a copy of the path in which every arc is replaced by
+// absolute cubic Bézier curves that deviate from it by no more than
+// tolerance. Arcs with a zero radius become lines and arcs with equal end
+// points are left out.
+func (p *Path) ArcsToCubics(tolerance float64) *Path {
+	result := &Path{}
+	cursor := &cursor{}
+	replaced := false
+
+	for _, command := range p.Commands {
+		absolute := cursor.absolute(command)
+		params := absolute.Params
+
+		switch {
+		case absolute.Symbol == "A":
+			to := Point{params[5], params[6]}
+			arc, ok := ArcCenter(cursor.current, to, params[0], params[1],
+				params[2], params[3] != 0, params[4] != 0)
+
+			if ok {
+				result.Commands = append(
+					result.Commands, arc.Cubics(tolerance)...)
+			} else if cursor.current != to {
+				result.Commands = append(result.Commands, &PathCommand{
+					Symbol: "L", Params: []float64{to.X, to.Y}})
+			}
+
+		case absolute.Symbol == "S" && replaced:
+			// A smooth curve after an arc has its first control point at
+			// the current point, so it must not be reflected off the curves
+			// that replaced the arc.
+			symbol, first := "C", cursor.current
+			if !command.IsAbsolute() {
+				symbol, first = "c", Point{}
+			}
+			result.Commands = append(result.Commands, &PathCommand{
+				Symbol: symbol,
+				Params: append([]float64{first.X, first.Y},
+					command.Params...),
+			})
+
+		default:
+			result.Commands = append(result.Commands, &PathCommand{
+				Symbol: command.Symbol,
+				Params: append([]float64{}, command.Params...),
+			})
+		}
+
+		replaced = absolute.Symbol == "A"
+		cursor.advance(absolute)
+	}
+
+	return result
+}
+
+// arcError estimates the maximum distance between a circular arc with the
+// given radius and sweep angle and its cubic Bézier approximation.
+func arcError(radius, sweep float64) float64 {
+	sin, cos := math.Sin(sweep/4), math.Cos(sweep/4)
+	return radius * 4 / 27 * math.Pow(sin, 6) / (cos * cos)
+}
+
+// angle returns the signed angle between the vectors (ux, uy) and (vx, vy).
+func angle(ux, uy, vx, vy float64) float64 {
+	return math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+}
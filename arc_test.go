@@ -0,0 +1,231 @@
+package svg_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+func TestArcCenter(t *testing.T) {
+	tests := []struct {
+		description string
+		from, to    Point
+		rx, ry      float64
+		rotation    float64
+		largeArc    bool
+		sweep       bool
+		expected    Arc
+	}{
+		{
+			description: "half circle with positive sweep",
+			from:        Point{0, 0},
+			to:          Point{10, 0},
+			rx:          5,
+			ry:          5,
+			sweep:       true,
+			expected: Arc{
+				Center: Point{5, 0}, RX: 5, RY: 5,
+				Start: math.Pi, Sweep: math.Pi,
+			},
+		},
+		{
+			description: "half circle with negative sweep",
+			from:        Point{0, 0},
+			to:          Point{10, 0},
+			rx:          5,
+			ry:          5,
+			expected: Arc{
+				Center: Point{5, 0}, RX: 5, RY: 5,
+				Start: math.Pi, Sweep: -math.Pi,
+			},
+		},
+		{
+			description: "radii too small",
+			from:        Point{0, 0},
+			to:          Point{10, 0},
+			rx:          1,
+			ry:          1,
+			sweep:       true,
+			expected: Arc{
+				Center: Point{5, 0}, RX: 5, RY: 5,
+				Start: math.Pi, Sweep: math.Pi,
+			},
+		},
+		{
+			description: "large arc",
+			from:        Point{0, 0},
+			to:          Point{10, 10},
+			rx:          10,
+			ry:          10,
+			largeArc:    true,
+			sweep:       true,
+			expected: Arc{
+				Center: Point{10, 0}, RX: 10, RY: 10,
+				Start: math.Pi, Sweep: 3 * math.Pi / 2,
+			},
+		},
+		{
+			description: "small arc",
+			from:        Point{0, 0},
+			to:          Point{10, 10},
+			rx:          10,
+			ry:          10,
+			sweep:       true,
+			expected: Arc{
+				Center: Point{0, 10}, RX: 10, RY: 10,
+				Start: -math.Pi / 2, Sweep: math.Pi / 2,
+			},
+		},
+		{
+			description: "rotated ellipse",
+			from:        Point{0, 0},
+			to:          Point{0, 20},
+			rx:          10,
+			ry:          5,
+			rotation:    90,
+			sweep:       true,
+			expected: Arc{
+				Center: Point{0, 10}, RX: 10, RY: 5,
+				Rotation: math.Pi / 2, Start: -math.Pi, Sweep: math.Pi,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			arc, ok := ArcCenter(test.from, test.to, test.rx, test.ry,
+				test.rotation, test.largeArc, test.sweep)
+			if !ok {
+				t.Fatalf("Arc: expected arc, actual none")
+			}
+
+			if !arcsClose(arc, test.expected) {
+				t.Errorf("Arc: expected %v, actual %v", test.expected, arc)
+			}
+
+			if end := arc.Point(arc.Start + arc.Sweep); !pointsClose(end, test.to) {
+				t.Errorf("Arc: expected end point %v, actual %v", test.to, end)
+			}
+		})
+	}
+}
+
+func TestArcCenterDegenerate(t *testing.T) {
+	tests := []struct {
+		description string
+		from, to    Point
+		rx, ry      float64
+	}{
+		{"zero radius", Point{0, 0}, Point{10, 0}, 0, 5},
+		{"equal end points", Point{10, 0}, Point{10, 0}, 5, 5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			if _, ok := ArcCenter(test.from, test.to, test.rx, test.ry,
+				0, false, false); ok {
+				t.Errorf("Arc: expected no arc")
+			}
+		})
+	}
+}
+
+func TestPathArcsToCubics(t *testing.T) {
+	tests := []struct {
+		description string
+		rawPath     string
+		tolerance   float64
+		expected    string
+	}{
+		{
+			description: "degenerate arcs",
+			rawPath:     "M 0 0 A 0 5 0 0 1 10 0 a 5 5 0 0 0 0 0 l 5 5",
+			expected:    "M 0 0 L 10 0 l 5 5",
+		},
+		{
+			description: "smooth curve after arc",
+			rawPath:     "M 0 0 A 5 5 0 0 1 10 0 s 5 5 10 0",
+			expected: "M 0 0 C 0 -2.761424 2.238576 -5 5 -5 " +
+				"C 7.761424 -5 10 -2.761424 10 0 c 0 0 5 5 10 0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			path, err := NewPath(test.rawPath)
+			if err != nil {
+				t.Fatalf("Path: unexpected error: %v", err)
+			}
+
+			actual := path.ArcsToCubics(test.tolerance).Format(
+				FormatOptions{Precision: Decimals(6)})
+			if actual != test.expected {
+				t.Errorf("Path: expected %q, actual %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPathArcsToCubicsTolerance(t *testing.T) {
+	for _, tolerance := range []float64{0, 1, 0.01, 0.0001} {
+		path, err := NewPath("M 0 0 A 100 100 0 1 1 0 1")
+		if err != nil {
+			t.Fatalf("Path: unexpected error: %v", err)
+		}
+
+		arc, _ := ArcCenter(Point{0, 0}, Point{0, 1}, 100, 100, 0, true, true)
+		converted := path.ArcsToCubics(tolerance)
+
+		if len(converted.Commands) < 5 {
+			t.Fatalf("Path: expected at least four curves, actual %v", converted)
+		}
+
+		limit := tolerance
+		if limit == 0 {
+			limit = 0.03
+		}
+
+		current := Point{0, 0}
+		for _, command := range converted.Commands[1:] {
+			p := command.Params
+			for i := 0; i <= 20; i++ {
+				point := cubicPoint(current, Point{p[0], p[1]},
+					Point{p[2], p[3]}, Point{p[4], p[5]}, float64(i)/20)
+
+				distance := math.Hypot(
+					point.X-arc.Center.X, point.Y-arc.Center.Y) - arc.RX
+				if math.Abs(distance) > limit {
+					t.Fatalf("Path: expected deviation below %v, actual %v",
+						limit, distance)
+				}
+			}
+			current = Point{p[4], p[5]}
+		}
+	}
+}
+
+func cubicPoint(p0, p1, p2, p3 Point, t float64) Point {
+	mt := 1 - t
+	a, b, c, d := mt*mt*mt, 3*mt*mt*t, 3*mt*t*t, t*t*t
+
+	return Point{
+		X: a*p0.X + b*p1.X + c*p2.X + d*p3.X,
+		Y: a*p0.Y + b*p1.Y + c*p2.Y + d*p3.Y,
+	}
+}
+
+func arcsClose(a, b Arc) bool {
+	return pointsClose(a.Center, b.Center) &&
+		floatsClose(a.RX, b.RX) && floatsClose(a.RY, b.RY) &&
+		floatsClose(a.Rotation, b.Rotation) &&
+		floatsClose(a.Start, b.Start) && floatsClose(a.Sweep, b.Sweep)
+}
+
+func pointsClose(a, b Point) bool {
+	return floatsClose(a.X, b.X) && floatsClose(a.Y, b.Y)
+}
+
+func floatsClose(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,139 @@
+package svg
+
+import "math"
+
+// Orientation is the direction in which a subpath runs around the area it
+// encloses.
+type Orientation int
+
+// Directions of subpaths, in the coordinate system of SVG, where the y-axis
+// points down.
+const (
+	// Degenerate subpaths enclose no area.
+	Degenerate Orientation = iota
+	Clockwise
+	CounterClockwise
+)
+
+var orientationNames = map[Orientation]string{
+	Degenerate:       "degenerate",
+	Clockwise:        "clockwise",
+	CounterClockwise: "counter-clockwise",
+}
+
+// String returns the name of the orientation.
+func (o Orientation) String() string {
+	if name, ok := orientationNames[o]; ok {
+		return name
+	}
+	return "unknown orientation"
+}
+
+// Area returns the signed area enclosed by each subpath, in the order of
+// Subpaths. Subpaths that are not closed are treated as if they were. The
+// area is positive for subpaths that run clockwise and negative for those
+// that run counter-clockwise. It is exact for lines and Bézier curves.
+func (p *Path) Area() []float64 {
+	var areas []float64
+
+	for _, subpath := range p.subpaths() {
+		area, _ := subpath.moments()
+		areas = append(areas, area)
+	}
+
+	return areas
+}
+
+// Orientation returns the direction of each subpath, in the order of
+// Subpaths. Subpaths whose area is negligible compared to the size of the
+// path are degenerate.
+func (p *Path) Orientation() []Orientation {
+	bounds := p.Bounds()
+	epsilon := 1e-12 * math.Max(bounds.Width(), bounds.Height()) *
+		math.Max(bounds.Width(), bounds.Height())
+
+	var orientations []Orientation
+	for _, area := range p.Area() {
+		switch {
+		case area > epsilon:
+			orientations = append(orientations, Clockwise)
+		case area < -epsilon:
+			orientations = append(orientations, CounterClockwise)
+		default:
+			orientations = append(orientations, Degenerate)
+		}
+	}
+
+	return orientations
+}
+
+// Centroid returns the center of mass of the area enclosed by the path. The
+// signed areas of the subpaths are added up, so holes must run in the
+// opposite direction to the subpaths around them. Returns false if the total
+// area is zero.
+func (p *Path) Centroid() (Point, bool) {
+	var area float64
+	var moment Point
+
+	for _, subpath := range p.subpaths() {
+		a, m := subpath.moments()
+		area += a
+		moment = moment.add(m)
+	}
+
+	if area == 0 {
+		return Point{}, false
+	}
+
+	return moment.mul(1 / area), true
+}
+
+// moments returns the signed area of the subpath and its first moments of
+// area, the integrals of x and y over the area. They follow from Green's
+// theorem as integrals along the outline of the subpath: the area is half
+// of the integral of x dy - y dx and the moments are a third of the integrals
+// of x (x dy - y dx) and y (x dy - y dx).
+func (s *subpath) moments() (float64, Point) {
+	segments := s.segments
+
+	end := s.start
+	if len(segments) > 0 {
+		end = segments[len(segments)-1].point(1)
+	}
+	if end != s.start {
+		segments = append(segments[:len(segments):len(segments)],
+			line{end, s.start})
+	}
+
+	var area float64
+	var moment Point
+
+	for _, segment := range segments {
+		a, m := segmentMoments(segment)
+		area += a
+		moment = moment.add(m)
+	}
+
+	return area / 2, moment.mul(1.0 / 3)
+}
+
+// segmentMoments returns the integrals of x dy - y dx and of x and y times
+// it along the segment. The integrands are polynomials of degree 8 at most
+// for Bézier curves, which the five point Gauss-Legendre rule integrates
+// exactly. Arcs are integrated numerically.
+func segmentMoments(s segment) (float64, Point) {
+	integral := gaussLegendre
+	if _, ok := s.(arcSegment); ok {
+		integral = integrate
+	}
+
+	area := func(t float64) float64 {
+		p, d := s.point(t), s.derivative(t)
+		return p.X*d.Y - p.Y*d.X
+	}
+
+	return integral(area, 0, 1), Point{
+		X: integral(func(t float64) float64 { return s.point(t).X * area(t) }, 0, 1),
+		Y: integral(func(t float64) float64 { return s.point(t).Y * area(t) }, 0, 1),
+	}
+}
@@ -0,0 +1,128 @@
+package svg_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+func TestPathArea(t *testing.T) {
+	tests := []struct {
+		description  string
+		rawPath      string
+		areas        []float64
+		orientations []Orientation
+		centroid     Point
+		hasCentroid  bool
+	}{
+		{
+			description:  "clockwise square",
+			rawPath:      "M 0 0 H 10 V 10 H 0 Z",
+			areas:        []float64{100},
+			orientations: []Orientation{Clockwise},
+			centroid:     Point{5, 5},
+			hasCentroid:  true,
+		},
+		{
+			description:  "counter-clockwise square",
+			rawPath:      "M 0 0 V 10 H 10 V 0 Z",
+			areas:        []float64{-100},
+			orientations: []Orientation{CounterClockwise},
+			centroid:     Point{5, 5},
+			hasCentroid:  true,
+		},
+		{
+			description:  "open subpath",
+			rawPath:      "M 0 0 H 10 V 10",
+			areas:        []float64{50},
+			orientations: []Orientation{Clockwise},
+			centroid:     Point{20.0 / 3, 10.0 / 3},
+			hasCentroid:  true,
+		},
+		{
+			description:  "hole",
+			rawPath:      "M 0 0 H 10 V 10 H 0 Z M 3 3 V 7 H 7 V 3 Z",
+			areas:        []float64{100, -16},
+			orientations: []Orientation{Clockwise, CounterClockwise},
+			centroid:     Point{5, 5},
+			hasCentroid:  true,
+		},
+		{
+			description:  "quadratic",
+			rawPath:      "M 0 0 Q 5 10 10 0 Z",
+			areas:        []float64{-100.0 / 3},
+			orientations: []Orientation{CounterClockwise},
+			centroid:     Point{5, 2},
+			hasCentroid:  true,
+		},
+		{
+			description:  "cubic",
+			rawPath:      "M 0 0 C 0 10 10 10 10 0 Z",
+			areas:        []float64{-60},
+			orientations: []Orientation{CounterClockwise},
+			centroid:     Point{5, 3.0 * 15 / 14},
+			hasCentroid:  true,
+		},
+		{
+			description:  "arcs",
+			rawPath:      "M 0 5 A 5 5 0 0 1 10 5 A 5 5 0 0 1 0 5 Z",
+			areas:        []float64{25 * math.Pi},
+			orientations: []Orientation{Clockwise},
+			centroid:     Point{5, 5},
+			hasCentroid:  true,
+		},
+		{
+			description:  "line",
+			rawPath:      "M 0 0 L 10 10",
+			areas:        []float64{0},
+			orientations: []Orientation{Degenerate},
+		},
+		{
+			description:  "moveto",
+			rawPath:      "M 5 5",
+			areas:        []float64{0},
+			orientations: []Orientation{Degenerate},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			path, err := NewPath(test.rawPath)
+			if err != nil {
+				t.Fatalf("Path: unexpected error: %v", err)
+			}
+
+			areas := path.Area()
+			if len(areas) != len(test.areas) {
+				t.Fatalf("Area: expected %v, actual %v", test.areas, areas)
+			}
+			for i, area := range test.areas {
+				if math.Abs(areas[i]-area) > 1e-8 {
+					t.Errorf("Area: expected %v, actual %v", test.areas, areas)
+				}
+			}
+
+			orientations := path.Orientation()
+			if len(orientations) != len(test.orientations) {
+				t.Fatalf("Orientation: expected %v, actual %v",
+					test.orientations, orientations)
+			}
+			for i, orientation := range test.orientations {
+				if orientations[i] != orientation {
+					t.Errorf("Orientation: expected %v, actual %v",
+						test.orientations, orientations)
+				}
+			}
+
+			centroid, ok := path.Centroid()
+			if ok != test.hasCentroid {
+				t.Fatalf("Centroid: expected %t, actual %t", test.hasCentroid, ok)
+			}
+			if math.Abs(centroid.X-test.centroid.X) > 1e-8 ||
+				math.Abs(centroid.Y-test.centroid.Y) > 1e-8 {
+				t.Errorf("Centroid: expected %v, actual %v", test.centroid, centroid)
+			}
+		})
+	}
+}
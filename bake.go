@@ -0,0 +1,381 @@
+package svg
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// BakeTransforms applies the transform attributes of the element and its
+// descendants to their geometry and removes them. Transforms of containers
+// are pushed down to their children. Path data is transformed directly and
+// basic shapes are converted to paths when the transformed shape cannot be
+// described by their own attributes. Stroke widths and dashes are scaled
+// along with the geometry.
+//
+// A transform is kept, combined with those of its ancestors, on elements it
+// cannot be baked into: elements that reference paint servers, clip paths,
+// masks, filters or markers, stroked elements under a transformation that
+// does not scale uniformly, and elements other than shapes and containers,
+// such as text, use, image and nested svg elements. Containers also keep
+// their transforms if any of their descendants has an id, as a use element
+// that references it does not apply the transforms of its ancestors.
+// Definitions are left untouched, as they are not rendered in place.
+func (e *Element) BakeTransforms() error {
+	return bakeTransforms(e, Identity, inherited{
+		stroke: "none", strokeWidth: "1", dashArray: "none", dashOffset: "0",
+	})
+}
+
+// inherited holds the stroke properties an element inherits.
+type inherited struct {
+	stroke      string
+	strokeWidth string
+	dashArray   string
+	dashOffset  string
+}
+
+// bakeContainers are elements whose transform can be pushed down to their
+// children.
+var bakeContainers = map[string]bool{"g": true, "a": true, "switch": true}
+
+// referencedOnly are elements whose content is only rendered when referenced.
+var referencedOnly = map[string]bool{
+	"defs": true, "clipPath": true, "mask": true, "pattern": true,
+	"marker": true, "symbol": true, "linearGradient": true,
+	"radialGradient": true, "filter": true, "style": true, "script": true,
+}
+
+// referenceProperties are properties that may refer to content defined in
+// the coordinate system of the element.
+var referenceProperties = []string{
+	"fill", "stroke", "clip-path", "mask", "filter",
+	"marker-start", "marker-mid", "marker-end", "marker",
+}
+
+func bakeTransforms(e *Element, pending Matrix, state inherited) error {
+	if referencedOnly[e.Name] {
+		return nil
+	}
+
+	matrix := pending
+	if raw, ok := e.Attributes["transform"]; ok {
+		own, _, err := ParseTransform(raw)
+		if err != nil {
+			return fmt.Errorf("Invalid transform of %s: %w", e.Name, err)
+		}
+		matrix = matrix.Multiply(own)
+	}
+
+	if stroke, ok := property(e, "stroke"); ok {
+		state.stroke = stroke
+	}
+	if width, ok := property(e, "stroke-width"); ok {
+		state.strokeWidth = width
+	}
+	if dashes, ok := property(e, "stroke-dasharray"); ok {
+		state.dashArray = dashes
+	}
+	if offset, ok := property(e, "stroke-dashoffset"); ok {
+		state.dashOffset = offset
+	}
+
+	switch {
+	case bakeContainers[e.Name] && !hasReferences(e) && !hasIDs(e.Children):
+		delete(e.Attributes, "transform")
+		for _, child := range e.Children {
+			if err := bakeTransforms(child, matrix, state); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case shapeAttributes[e.Name] != nil && bakeShape(e, matrix, state):
+		delete(e.Attributes, "transform")
+		return nil
+	}
+
+	// An element that keeps its transform is left as it is unless it has
+	// to take over the transforms of its ancestors.
+	if !pending.IsIdentity() {
+		setTransform(e, matrix)
+	}
+
+	for _, child := range e.Children {
+		if err := bakeTransforms(child, Identity, state); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bakeShape applies the matrix to the geometry and stroke of a path or basic
+// shape. Returns false and leaves the element unchanged if that is not
+// possible.
+func bakeShape(e *Element, matrix Matrix, state inherited) bool {
+	if matrix.IsIdentity() {
+		return true
+	}
+
+	if hasReferences(e) {
+		return false
+	}
+
+	if e.Attributes == nil {
+		e.Attributes = map[string]string{}
+	}
+
+	stroked := state.stroke != "none" && state.stroke != ""
+	if effect, _ := property(e, "vector-effect"); effect == "non-scaling-stroke" {
+		stroked = false
+	}
+
+	// A stroke can only be scaled along with the geometry if its width is
+	// scaled the same way in all directions.
+	scale := math.Sqrt(math.Abs(matrix.Determinant()))
+	strokeValues := map[string]string{}
+
+	if stroked && !isSimilarity(matrix) {
+		return false
+	}
+
+	if stroked && scale != 1 {
+		width, err := parseLength(state.strokeWidth)
+		if err != nil {
+			return false
+		}
+		strokeValues["stroke-width"] = formatNumber(width*scale, -1, false)
+
+		dashes := map[string]string{
+			"stroke-dasharray":  state.dashArray,
+			"stroke-dashoffset": state.dashOffset,
+		}
+		for name, raw := range dashes {
+			if raw == "none" || raw == "0" {
+				continue
+			}
+
+			numbers, err := parseNumbers(raw)
+			if err != nil {
+				return false
+			}
+			strokeValues[name] = formatList(numbers, scale)
+		}
+	}
+
+	attributes, ok := transformShape(e, matrix)
+	if !ok {
+		return false
+	}
+
+	if _, ok := attributes["d"]; ok && e.Name != "path" {
+		for _, name := range shapeAttributes[e.Name] {
+			delete(e.Attributes, name)
+		}
+		e.Name = "path"
+	}
+
+	for name, value := range attributes {
+		e.Attributes[name] = value
+	}
+
+	for name, value := range strokeValues {
+		setProperty(e, name, value)
+	}
+
+	return true
+}
+
+// transformShape returns the geometry attributes of the shape element after
+// the matrix is applied to it. Shapes that cannot describe the transformed
+// geometry are returned as path data. Returns false if the geometry cannot be
+// parsed.
+func transformShape(e *Element, matrix Matrix) (map[string]string, bool) {
+	format := func(value float64) string {
+		return formatNumber(value, -1, false)
+	}
+
+	axisAligned := matrix.B == 0 && matrix.C == 0
+	sx, sy := math.Abs(matrix.A), math.Abs(matrix.D)
+
+	switch {
+	case e.Name == "line":
+		path, err := e.ToPath()
+		if err != nil {
+			return nil, false
+		}
+
+		p := path.Transform(matrix).Commands
+		return map[string]string{
+			"x1": format(p[0].Params[0]), "y1": format(p[0].Params[1]),
+			"x2": format(p[1].Params[0]), "y2": format(p[1].Params[1]),
+		}, true
+
+	case e.Name == "polyline" || e.Name == "polygon":
+		points, err := parsePoints(e.Attributes["points"])
+		if err != nil {
+			return nil, false
+		}
+
+		for i, point := range points {
+			points[i] = matrix.Apply(point)
+		}
+		return map[string]string{"points": formatPoints(points)}, true
+
+	case e.Name == "rect" && axisAligned:
+		lengths, err := shapeLengths(e)
+		if err != nil {
+			return nil, false
+		}
+
+		rx, ry := cornerRadii(lengths)
+		corner := matrix.Apply(Point{lengths["x"], lengths["y"]})
+		width, height := lengths["width"]*sx, lengths["height"]*sy
+
+		// A reflection moves the corner to the other side.
+		if matrix.A < 0 {
+			corner.X -= width
+		}
+		if matrix.D < 0 {
+			corner.Y -= height
+		}
+
+		attributes := map[string]string{
+			"x": format(corner.X), "y": format(corner.Y),
+			"width": format(width), "height": format(height),
+		}
+		if rx > 0 && ry > 0 {
+			attributes["rx"] = format(rx * sx)
+			attributes["ry"] = format(ry * sy)
+		}
+		return attributes, true
+
+	case e.Name == "circle" && axisAligned && sx == sy,
+		e.Name == "ellipse" && axisAligned:
+
+		lengths, err := shapeLengths(e)
+		if err != nil {
+			return nil, false
+		}
+
+		center := matrix.Apply(Point{lengths["cx"], lengths["cy"]})
+		attributes := map[string]string{
+			"cx": format(center.X), "cy": format(center.Y),
+		}
+		if e.Name == "circle" {
+			attributes["r"] = format(lengths["r"] * sx)
+		} else {
+			attributes["rx"] = format(lengths["rx"] * sx)
+			attributes["ry"] = format(lengths["ry"] * sy)
+		}
+		return attributes, true
+	}
+
+	path, err := e.ToPath()
+	if err != nil {
+		return nil, false
+	}
+
+	return map[string]string{"d": path.Transform(matrix).String()}, true
+}
+
+// isSimilarity returns true if the matrix scales equally in all directions,
+// i.e. it is a combination of a uniform scale, rotations, reflections and
+// translations.
+func isSimilarity(m Matrix) bool {
+	const epsilon = 1e-9
+
+	near := func(a, b float64) bool {
+		return math.Abs(a-b) <= epsilon*math.Max(1, math.Abs(a)+math.Abs(b))
+	}
+
+	return (near(m.A, m.D) && near(m.B, -m.C)) ||
+		(near(m.A, -m.D) && near(m.B, m.C))
+}
+
+// hasReferences returns true if any property of the element refers to other
+// content with url().
+func hasReferences(e *Element) bool {
+	for _, name := range referenceProperties {
+		if value, ok := property(e, name); ok && strings.Contains(value, "url(") {
+			return true
+		}
+	}
+	return false
+}
+
+// hasIDs returns true if any of the elements or their descendants has an id.
+// Definitions are left out, as baking does not change them.
+func hasIDs(elements []*Element) bool {
+	for _, e := range elements {
+		if referencedOnly[e.Name] {
+			continue
+		}
+		if _, ok := e.Attributes["id"]; ok || hasIDs(e.Children) {
+			return true
+		}
+	}
+	return false
+}
+
+// setTransform sets the transform attribute of the element to the matrix, or
+// removes it for the identity matrix.
+func setTransform(e *Element, matrix Matrix) {
+	if matrix.IsIdentity() {
+		delete(e.Attributes, "transform")
+		return
+	}
+
+	if e.Attributes == nil {
+		e.Attributes = map[string]string{}
+	}
+	e.Attributes["transform"] = matrix.String()
+}
+
+// property returns the value of a presentation property of the element. A
+// declaration in the style attribute takes precedence over the attribute.
+func property(e *Element, name string) (string, bool) {
+	for _, declaration := range strings.Split(e.Attributes["style"], ";") {
+		parts := strings.SplitN(declaration, ":", 2)
+		if len(parts) == 2 && strings.TrimSpace(parts[0]) == name {
+			return strings.TrimSpace(parts[1]), true
+		}
+	}
+
+	value, ok := e.Attributes[name]
+	return strings.TrimSpace(value), ok
+}
+
+// setProperty sets a presentation property of the element, in the style
+// attribute if it is declared there and as an attribute otherwise.
+func setProperty(e *Element, name, value string) {
+	declarations := strings.Split(e.Attributes["style"], ";")
+
+	for i, declaration := range declarations {
+		parts := strings.SplitN(declaration, ":", 2)
+		if len(parts) == 2 && strings.TrimSpace(parts[0]) == name {
+			declarations[i] = name + ":" + value
+			e.Attributes["style"] = strings.Join(declarations, ";")
+			return
+		}
+	}
+
+	e.Attributes[name] = value
+}
+
+// formatList writes the numbers, multiplied by factor, as a comma separated
+// list.
+func formatList(numbers []float64, factor float64) string {
+	buf := &bytes.Buffer{}
+
+	for i, number := range numbers {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(formatNumber(number*factor, -1, false))
+	}
+
+	return buf.String()
+}
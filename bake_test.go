@@ -0,0 +1,329 @@
+package svg_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+func TestElementBakeTransforms(t *testing.T) {
+	tests := []struct {
+		description string
+		raw         string
+		expected    *Element
+	}{
+		{
+			description: "path in translated group",
+			raw: `<svg><g transform="translate(10 20)" fill="red">` +
+				`<path d="m 0 0 h 5 v 5 z" transform="scale(2)"/></g></svg>`,
+			expected: &Element{
+				Name: "svg",
+				Children: []*Element{
+					{
+						Name:       "g",
+						Attributes: map[string]string{"fill": "red"},
+						Children: []*Element{
+							{
+								Name: "path",
+								Attributes: map[string]string{
+									"d": "M 10 20 H 20 V 30 Z",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "shapes under axis-aligned scale",
+			raw: `<svg><g transform="translate(1 1) scale(2 -3)">` +
+				`<rect x="1" y="1" width="2" height="4" rx="1"/>` +
+				`<ellipse cx="1" cy="2" rx="3" ry="4"/>` +
+				`<line x1="1" y1="2" x2="3" y2="4"/>` +
+				`<polygon points="0,0 1,1"/></g></svg>`,
+			expected: &Element{
+				Name: "svg",
+				Children: []*Element{
+					{
+						Name: "g",
+						Children: []*Element{
+							{
+								Name: "rect",
+								Attributes: map[string]string{
+									"x": "3", "y": "-14", "width": "4",
+									"height": "12", "rx": "2", "ry": "3",
+								},
+							},
+							{
+								Name: "ellipse",
+								Attributes: map[string]string{
+									"cx": "3", "cy": "-5", "rx": "6", "ry": "12",
+								},
+							},
+							{
+								Name: "line",
+								Attributes: map[string]string{
+									"x1": "3", "y1": "-5", "x2": "7", "y2": "-11",
+								},
+							},
+							{
+								Name: "polygon",
+								Attributes: map[string]string{
+									"points": "1,1 3,-2",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "circle under non-uniform scale becomes path",
+			raw:         `<svg><circle r="1" transform="scale(2 1)" fill="blue"/></svg>`,
+			expected: &Element{
+				Name: "svg",
+				Children: []*Element{
+					{
+						Name: "path",
+						Attributes: map[string]string{
+							"fill": "blue",
+							"d":    "M 2 0 A 2 1 0 0 1 -2 0 A 2 1 0 0 1 2 0 Z",
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "stroke width is scaled",
+			raw: `<svg><g stroke="black" stroke-width="2" transform="scale(3)">` +
+				`<line x2="1" style="stroke-dasharray: 1 2"/>` +
+				`<line x2="1" stroke-width="0.5"/></g></svg>`,
+			expected: &Element{
+				Name: "svg",
+				Children: []*Element{
+					{
+						Name: "g",
+						Attributes: map[string]string{
+							"stroke": "black", "stroke-width": "2",
+						},
+						Children: []*Element{
+							{
+								Name: "line",
+								Attributes: map[string]string{
+									"x1": "0", "y1": "0", "x2": "3", "y2": "0",
+									"stroke-width": "6",
+									"style":        "stroke-dasharray:3,6",
+								},
+							},
+							{
+								Name: "line",
+								Attributes: map[string]string{
+									"x1": "0", "y1": "0", "x2": "3", "y2": "0",
+									"stroke-width": "1.5",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "inherited dashes are scaled",
+			raw: `<svg><g transform="scale(2)" stroke="black" stroke-dasharray="5 5">` +
+				`<path d="M0 0 L10 0"/></g></svg>`,
+			expected: &Element{
+				Name: "svg",
+				Children: []*Element{
+					{
+						Name: "g",
+						Attributes: map[string]string{
+							"stroke": "black", "stroke-dasharray": "5 5",
+						},
+						Children: []*Element{
+							{
+								Name: "path",
+								Attributes: map[string]string{
+									"d":                "M 0 0 L 20 0",
+									"stroke-width":     "2",
+									"stroke-dasharray": "10,10",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "stroked shape under non-uniform scale keeps transform",
+			raw: `<svg><g transform="scale(2 1)"><path d="M 0 0 H 1" stroke="red"/>` +
+				`<path d="M 0 0 H 1"/></g></svg>`,
+			expected: &Element{
+				Name: "svg",
+				Children: []*Element{
+					{
+						Name: "g",
+						Children: []*Element{
+							{
+								Name: "path",
+								Attributes: map[string]string{
+									"d": "M 0 0 H 1", "stroke": "red",
+									"transform": "scale(2 1)",
+								},
+							},
+							{
+								Name:       "path",
+								Attributes: map[string]string{"d": "M 0 0 H 2"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "elements that cannot be baked",
+			raw: `<svg><g transform="translate(5)">` +
+				`<text transform="scale(2)">Hi</text>` +
+				`<rect width="1" height="1" fill="url(#a)"/>` +
+				`<defs><path id="p" d="M 0 0" transform="scale(2)"/></defs>` +
+				`</g></svg>`,
+			expected: &Element{
+				Name: "svg",
+				Children: []*Element{
+					{
+						Name: "g",
+						Children: []*Element{
+							{
+								Name:       "text",
+								Content:    "Hi",
+								Attributes: map[string]string{"transform": "matrix(2 0 0 2 5 0)"},
+							},
+							{
+								Name: "rect",
+								Attributes: map[string]string{
+									"width": "1", "height": "1", "fill": "url(#a)",
+									"transform": "translate(5)",
+								},
+							},
+							{
+								Name: "defs",
+								Children: []*Element{
+									{
+										Name: "path",
+										Attributes: map[string]string{
+											"id": "p", "d": "M 0 0",
+											"transform": "scale(2)",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "group with clip path keeps transform",
+			raw: `<svg><g transform="scale(2)" clip-path="url(#c)">` +
+				`<path d="M 1 1" transform="translate(1 1)"/></g></svg>`,
+			expected: &Element{
+				Name: "svg",
+				Children: []*Element{
+					{
+						Name: "g",
+						Attributes: map[string]string{
+							"transform": "scale(2)", "clip-path": "url(#c)",
+						},
+						Children: []*Element{
+							{
+								Name:       "path",
+								Attributes: map[string]string{"d": "M 2 2"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "group with referenced descendant keeps transform",
+			raw: `<svg><g transform="translate(10 0)"><g>` +
+				`<path id="a" d="M 0 0 H 5" transform="scale(2)"/></g></g>` +
+				`<use href="#a"/></svg>`,
+			expected: &Element{
+				Name: "svg",
+				Children: []*Element{
+					{
+						Name: "g",
+						Attributes: map[string]string{
+							"transform": "translate(10 0)",
+						},
+						Children: []*Element{
+							{
+								Name: "g",
+								Children: []*Element{
+									{
+										Name: "path",
+										Attributes: map[string]string{
+											"id": "a", "d": "M 0 0 H 10",
+										},
+									},
+								},
+							},
+						},
+					},
+					{
+						Name:       "use",
+						Attributes: map[string]string{"href": "#a"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			root, err := New(strings.NewReader(test.raw))
+			if err != nil {
+				t.Fatalf("New: unexpected error: %s", err)
+			}
+
+			if err := root.BakeTransforms(); err != nil {
+				t.Fatalf("Element: unexpected error: %s", err)
+			}
+
+			if !test.expected.Equal(root) {
+				t.Errorf("Element: expected %v, actual %v",
+					render(t, test.expected), render(t, root))
+			}
+		})
+	}
+}
+
+func TestElementBakeTransformsErrors(t *testing.T) {
+	root := &Element{
+		Name: "svg",
+		Children: []*Element{
+			{Name: "g", Attributes: map[string]string{"transform": "skewX()"}},
+		},
+	}
+
+	err := root.BakeTransforms()
+	if err == nil || !strings.HasPrefix(err.Error(), "Invalid transform of g") {
+		t.Errorf("Element: expected transform error, actual %v", err)
+	}
+
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("Element: expected a SyntaxError, actual %v", err)
+	}
+}
+
+func render(t *testing.T, e *Element) string {
+	buf := &bytes.Buffer{}
+	if err := e.Render(buf); err != nil {
+		t.Fatalf("Render: unexpected error: %s", err)
+	}
+	return buf.String()
+}
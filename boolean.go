@@ -0,0 +1,578 @@
+package svg
+
+import (
+	"math"
+	"sort"
+)
+
+// Union returns the area covered by either path, as a new path. Both paths
+// are filled with the rule, and curves are approximated by lines within a
+// ten thousandth of the size of the paths. The result is made of closed
+// polygons whose outer boundaries run clockwise and whose holes run counter
+// clockwise, so it has the same area with either fill rule.
+func (p *Path) Union(o *Path, rule FillRule) *Path {
+	return combine(p, o, rule, func(a, b bool) bool { return a || b })
+}
+
+// Intersection returns the area covered by both paths, as a new path built
+// the same way as by Union.
+func (p *Path) Intersection(o *Path, rule FillRule) *Path {
+	return combine(p, o, rule, func(a, b bool) bool { return a && b })
+}
+
+// Difference returns the area covered by the path but not by the other, as
+// a new path built the same way as by Union.
+func (p *Path) Difference(o *Path, rule FillRule) *Path {
+	return combine(p, o, rule, func(a, b bool) bool { return a && !b })
+}
+
+// Xor returns the area covered by exactly one of the paths, as a new path
+// built the same way as by Union.
+func (p *Path) Xor(o *Path, rule FillRule) *Path {
+	return combine(p, o, rule, func(a, b bool) bool { return a != b })
+}
+
+// clipEdge is a line of the outline of one of the operands.
+type clipEdge struct {
+	from, to Point
+}
+
+// clipPart is a part of one or more edges between the points where they are
+// split.
+type clipPart struct {
+	from, to vertex
+	edges    []clipEdge
+}
+
+// vertex identifies points that are merged together, by the cell of the grid
+// that the first of them falls in.
+type vertex [2]int64
+
+// clipper holds the state of a boolean operation.
+type clipper struct {
+	edges [2][]clipEdge
+	rule  FillRule
+	op    func(a, b bool) bool
+
+	// merge is the distance within which points are merged and within
+	// which the ends of edges split other edges.
+	merge  float64
+	points map[vertex]Point
+}
+
+// combine returns the area where op is true for the areas covered by the
+// paths. It splits the edges of both paths where they cross, keeps the parts
+// that separate the inside of the result from the outside and joins them
+// into polygons.
+func combine(a, b *Path, rule FillRule, op func(a, b bool) bool) *Path {
+	bounds, ok := combinedBounds(a, b)
+	if !ok {
+		return &Path{}
+	}
+
+	scale := math.Max(bounds.Width(), bounds.Height())
+	if scale == 0 {
+		return &Path{}
+	}
+
+	c := &clipper{
+		rule:   rule,
+		op:     op,
+		merge:  scale * 1e-7,
+		points: map[vertex]Point{},
+	}
+
+	for operand, path := range []*Path{a, b} {
+		for _, polyline := range path.polylines(scale * 1e-4) {
+			points := polyline.points
+			for i, from := range points {
+				to := points[(i+1)%len(points)]
+				if c.key(from) != c.key(to) {
+					c.edges[operand] = append(c.edges[operand], clipEdge{from, to})
+				}
+			}
+		}
+	}
+
+	return c.stitch(c.classify(c.split()))
+}
+
+// combinedBounds returns the bounds of the points of both paths.
+func combinedBounds(a, b *Path) (Rect, bool) {
+	var bounds Rect
+	found := false
+
+	for _, path := range []*Path{a, b} {
+		if len(path.Commands) == 0 {
+			continue
+		}
+
+		other := path.Bounds()
+		if !found {
+			bounds, found = other, true
+			continue
+		}
+		bounds = bounds.extend(other.Min).extend(other.Max)
+	}
+
+	return bounds, found
+}
+
+// key returns the vertex of the point: that of an earlier point within the
+// merge distance, or else a new one, which remembers the point. The grid has
+// cells of half the merge distance, so nearby points are found in the cells
+// at most two away.
+func (c *clipper) key(p Point) vertex {
+	cell := vertex{
+		int64(math.Floor(p.X / c.merge * 2)), int64(math.Floor(p.Y / c.merge * 2)),
+	}
+
+	for dx := int64(-2); dx <= 2; dx++ {
+		for dy := int64(-2); dy <= 2; dy++ {
+			v := vertex{cell[0] + dx, cell[1] + dy}
+			if point, ok := c.points[v]; ok && point.sub(p).length() <= c.merge {
+				return v
+			}
+		}
+	}
+
+	if _, ok := c.points[cell]; !ok {
+		c.points[cell] = p
+	}
+	return cell
+}
+
+// split splits the edges of both operands at the points where they cross or
+// touch each other and returns the parts, without duplicates. Edges are also
+// split at the ends of other edges that lie within the merge distance of
+// them, so that edges that nearly touch are joined.
+func (c *clipper) split() []clipPart {
+	edges := append(append([]clipEdge{}, c.edges[0]...), c.edges[1]...)
+
+	type cut struct {
+		t     float64
+		point Point
+	}
+	cuts := make([][]cut, len(edges))
+
+	// Edges are visited from left to right, so each is only compared with
+	// the edges that start before it ends.
+	order := make([]int, len(edges))
+	for i := range order {
+		order[i] = i
+	}
+	left := func(e clipEdge) float64 { return math.Min(e.from.X, e.to.X) }
+	sort.Slice(order, func(a, b int) bool {
+		return left(edges[order[a]]) < left(edges[order[b]])
+	})
+
+	for k, i := range order {
+		right := math.Max(edges[i].from.X, edges[i].to.X) + c.merge
+
+		for _, j := range order[k+1:] {
+			if left(edges[j]) > right {
+				break
+			}
+
+			p, q := edges[i], edges[j]
+			if !overlaps(p, q, c.merge) {
+				continue
+			}
+
+			for _, end := range []Point{q.from, q.to} {
+				if t, ok := c.near(p, end); ok {
+					cuts[i] = append(cuts[i], cut{t, end})
+				}
+			}
+			for _, end := range []Point{p.from, p.to} {
+				if u, ok := c.near(q, end); ok {
+					cuts[j] = append(cuts[j], cut{u, end})
+				}
+			}
+
+			r, s := p.to.sub(p.from), q.to.sub(q.from)
+			denominator := cross(r, s)
+			if math.Abs(denominator) <= 1e-12*r.length()*s.length() {
+				continue
+			}
+
+			offset := q.from.sub(p.from)
+			t := cross(offset, s) / denominator
+			u := cross(offset, r) / denominator
+			if t < 0 || t > 1 || u < 0 || u > 1 {
+				continue
+			}
+
+			// Crossings next to the end of either edge are imprecise for
+			// nearly parallel edges, and are covered by the splits at the
+			// ends.
+			point := p.from.lerp(p.to, t)
+			if c.nearEnd(point, p) || c.nearEnd(point, q) {
+				continue
+			}
+
+			cuts[i] = append(cuts[i], cut{t, point})
+			cuts[j] = append(cuts[j], cut{u, point})
+		}
+	}
+
+	var parts []clipPart
+	seen := map[[2]vertex]int{}
+
+	for i, e := range edges {
+		sort.Slice(cuts[i], func(a, b int) bool { return cuts[i][a].t < cuts[i][b].t })
+
+		from := c.key(e.from)
+		for _, point := range append(cuts[i], cut{1, e.to}) {
+			to := c.key(point.point)
+			if to == from {
+				continue
+			}
+
+			// Parts shared by several edges, in either direction, are
+			// classified once.
+			undirected := [2]vertex{from, to}
+			if less(to, from) {
+				undirected = [2]vertex{to, from}
+			}
+			if k, ok := seen[undirected]; ok {
+				parts[k].edges = append(parts[k].edges, e)
+			} else {
+				seen[undirected] = len(parts)
+				parts = append(parts, clipPart{from, to, []clipEdge{e}})
+			}
+
+			from = to
+		}
+	}
+
+	return parts
+}
+
+// near returns the position along the edge of the point closest to p, if it
+// lies between the ends of the edge and within the merge distance of p.
+func (c *clipper) near(e clipEdge, p Point) (float64, bool) {
+	r := e.to.sub(e.from)
+	t := p.sub(e.from).dot(r) / r.dot(r)
+	if t <= 0 || t >= 1 {
+		return 0, false
+	}
+
+	return t, e.from.lerp(e.to, t).sub(p).length() <= c.merge
+}
+
+// nearEnd returns true if the point is within the merge distance of either
+// end of the edge.
+func (c *clipper) nearEnd(p Point, e clipEdge) bool {
+	return p.sub(e.from).length() <= c.merge || p.sub(e.to).length() <= c.merge
+}
+
+// classify returns the parts that lie on the boundary of the result, directed
+// so that the inside of the result is on their right. The sides of each part
+// are tested along a horizontal or vertical line through its middle,
+// whichever is further from parallel to the part.
+func (c *clipper) classify(parts []clipPart) [][2]vertex {
+	rows := newEdgeIndex(c.edges, 0)
+	columns := newEdgeIndex(c.edges, 1)
+
+	var boundary [][2]vertex
+
+	for _, part := range parts {
+		from, to := c.points[part.from], c.points[part.to]
+		middle := from.lerp(to, 0.5)
+		right := normal(from, to)
+
+		index, towards := rows, right.X
+		if math.Abs(right.Y) > math.Abs(right.X) {
+			index, towards = columns, right.Y
+		}
+
+		inRight, inLeft := c.sides(index, middle, part.edges)
+		if towards < 0 {
+			inRight, inLeft = inLeft, inRight
+		}
+
+		switch {
+		case inRight && !inLeft:
+			boundary = append(boundary, [2]vertex{part.from, part.to})
+		case inLeft && !inRight:
+			boundary = append(boundary, [2]vertex{part.to, part.from})
+		}
+	}
+
+	return boundary
+}
+
+// sides returns whether the points next to p are inside the result, after
+// and before p along the axis of the index. The winding numbers of both
+// operands are counted along the line through p, leaving out the edges that
+// p lies on. As edges are split where they cross, no other edge passes
+// through p.
+func (c *clipper) sides(index *edgeIndex, p Point, on []clipEdge) (bool, bool) {
+	along, across := coordinate(p, index.axis), coordinate(p, 1-index.axis)
+	var after, before [2]int
+
+	for _, e := range index.band(across) {
+		if hasEdge(on, e.clipEdge) {
+			continue
+		}
+
+		from := coordinate(e.from, 1-index.axis) - across
+		to := coordinate(e.to, 1-index.axis) - across
+
+		var crossing int
+		switch {
+		case from <= 0 && to > 0:
+			crossing = 1
+		case to <= 0 && from > 0:
+			crossing = -1
+		default:
+			continue
+		}
+
+		t := from / (from - to)
+		position := coordinate(e.from.lerp(e.to, t), index.axis)
+		switch {
+		case position > along:
+			after[e.operand] += crossing
+		case position < along:
+			before[e.operand] += crossing
+		}
+	}
+
+	return c.op(c.filled(after[0]), c.filled(after[1])),
+		c.op(c.filled(before[0]), c.filled(before[1]))
+}
+
+// filled returns true if the winding number is inside according to the fill
+// rule.
+func (c *clipper) filled(winding int) bool {
+	if c.rule == EvenOdd {
+		return winding%2 != 0
+	}
+	return winding != 0
+}
+
+// operandEdge is an edge of one of the operands.
+type operandEdge struct {
+	clipEdge
+	operand int
+}
+
+// edgeIndex holds the edges of both operands in bands across an axis, so
+// that the edges that cross a line along the axis are found quickly.
+type edgeIndex struct {
+	axis      int
+	min, size float64
+	bands     [][]operandEdge
+}
+
+// newEdgeIndex returns the index of the edges for lines along the axis, 0
+// for horizontal lines and 1 for vertical ones.
+func newEdgeIndex(edges [2][]clipEdge, axis int) *edgeIndex {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, operand := range edges {
+		for _, e := range operand {
+			for _, end := range []Point{e.from, e.to} {
+				low = math.Min(low, coordinate(end, 1-axis))
+				high = math.Max(high, coordinate(end, 1-axis))
+			}
+		}
+	}
+
+	count := int(math.Sqrt(float64(len(edges[0])+len(edges[1])))) + 1
+	index := &edgeIndex{
+		axis:  axis,
+		min:   low,
+		size:  (high - low) / float64(count),
+		bands: make([][]operandEdge, count),
+	}
+
+	for operand, list := range edges {
+		for _, e := range list {
+			first := index.bandNumber(coordinate(e.from, 1-axis))
+			last := index.bandNumber(coordinate(e.to, 1-axis))
+			if first > last {
+				first, last = last, first
+			}
+
+			for i := first; i <= last; i++ {
+				index.bands[i] = append(index.bands[i], operandEdge{e, operand})
+			}
+		}
+	}
+
+	return index
+}
+
+// band returns the edges that may cross the line at the value across the
+// axis.
+func (x *edgeIndex) band(value float64) []operandEdge {
+	return x.bands[x.bandNumber(value)]
+}
+
+// bandNumber returns the band that the value across the axis falls in.
+func (x *edgeIndex) bandNumber(value float64) int {
+	if !(x.size > 0) {
+		return 0
+	}
+
+	i := int((value - x.min) / x.size)
+	if i < 0 {
+		return 0
+	}
+	if i >= len(x.bands) {
+		return len(x.bands) - 1
+	}
+	return i
+}
+
+// coordinate returns the x coordinate of the point for axis 0 and the y
+// coordinate for axis 1.
+func coordinate(p Point, axis int) float64 {
+	if axis == 0 {
+		return p.X
+	}
+	return p.Y
+}
+
+// stitch joins the directed parts into closed polygons and returns them as
+// a path. Every vertex should have as many parts leaving it as arriving at
+// it, but imprecise crossings may leave gaps in the outline. Walks are
+// therefore started at the vertices that more parts leave than arrive at, so
+// that a walk that cannot return to its start runs up to a gap, where it is
+// closed with a line.
+func (c *clipper) stitch(parts [][2]vertex) *Path {
+	outgoing := map[vertex][]int{}
+	balance := map[vertex]int{}
+	for i, part := range parts {
+		outgoing[part[0]] = append(outgoing[part[0]], i)
+		balance[part[0]]++
+		balance[part[1]]--
+	}
+
+	order := make([]int, len(parts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return balance[parts[order[a]][0]] > balance[parts[order[b]][0]]
+	})
+
+	used := make([]bool, len(parts))
+	result := &Path{}
+
+	for _, i := range order {
+		if used[i] {
+			continue
+		}
+
+		start := parts[i][0]
+		vertices := []vertex{start}
+		current := i
+
+		for {
+			used[current] = true
+			end := parts[current][1]
+			if end == start {
+				break
+			}
+			vertices = append(vertices, end)
+
+			current = c.next(parts, outgoing[end], used, current)
+			if current < 0 {
+				break
+			}
+		}
+
+		polygon := c.simplify(vertices)
+		if len(polygon) >= 3 {
+			result.Commands = append(result.Commands,
+				polylinePath(polygon, true).Commands...)
+		}
+	}
+
+	return result
+}
+
+// next returns the unused part to continue the polygon with after the part
+// that ends where the candidates start, or -1 if there is none. It takes the
+// sharpest turn to the right, towards the inside, so polygons that touch at
+// a point are kept apart.
+func (c *clipper) next(parts [][2]vertex, candidates []int, used []bool,
+	previous int) int {
+
+	in := c.points[parts[previous][1]].sub(c.points[parts[previous][0]])
+
+	best, bestTurn := -1, 0.0
+	for _, candidate := range candidates {
+		if used[candidate] {
+			continue
+		}
+
+		out := c.points[parts[candidate][1]].sub(c.points[parts[candidate][0]])
+		turn := math.Atan2(cross(in, out), in.dot(out))
+		if best < 0 || turn > bestTurn {
+			best, bestTurn = candidate, turn
+		}
+	}
+
+	return best
+}
+
+// simplify returns the points of the polygon without the points that lie
+// on a straight line between their neighbours.
+func (c *clipper) simplify(vertices []vertex) []Point {
+	points := make([]Point, len(vertices))
+	for i, v := range vertices {
+		points[i] = c.points[v]
+	}
+
+	for changed := true; changed && len(points) >= 3; {
+		changed = false
+
+		for i := 0; i < len(points) && len(points) >= 3; i++ {
+			previous := points[(i+len(points)-1)%len(points)]
+			next := points[(i+1)%len(points)]
+			in, out := points[i].sub(previous), next.sub(points[i])
+
+			if math.Abs(cross(in, out)) <= 1e-9*in.length()*out.length() &&
+				in.dot(out) > 0 {
+				points = append(points[:i], points[i+1:]...)
+				changed = true
+				i--
+			}
+		}
+	}
+
+	return points
+}
+
+// overlaps returns true if the bounding boxes of the edges overlap, or are
+// within the margin of each other.
+func overlaps(a, b clipEdge, margin float64) bool {
+	return math.Max(a.from.X, a.to.X)+margin >= math.Min(b.from.X, b.to.X) &&
+		math.Max(b.from.X, b.to.X)+margin >= math.Min(a.from.X, a.to.X) &&
+		math.Max(a.from.Y, a.to.Y)+margin >= math.Min(b.from.Y, b.to.Y) &&
+		math.Max(b.from.Y, b.to.Y)+margin >= math.Min(a.from.Y, a.to.Y)
+}
+
+// hasEdge returns true if the edge is one of the edges.
+func hasEdge(edges []clipEdge, e clipEdge) bool {
+	for _, other := range edges {
+		if other == e {
+			return true
+		}
+	}
+	return false
+}
+
+// cross returns the z component of the cross product of the vectors.
+func cross(a, b Point) float64 {
+	return a.X*b.Y - a.Y*b.X
+}
+
+// less orders vertices.
+func less(a, b vertex) bool {
+	return a[0] < b[0] || (a[0] == b[0] && a[1] < b[1])
+}
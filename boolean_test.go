@@ -0,0 +1,231 @@
+package svg_test
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+func TestPathBoolean(t *testing.T) {
+	type sample struct {
+		point  Point
+		inside bool
+	}
+
+	tests := []struct {
+		description string
+		rawA, rawB  string
+		rule        FillRule
+		operation   func(a, b *Path, rule FillRule) *Path
+		subpaths    int
+		samples     []sample
+	}{
+		{
+			description: "union",
+			rawA:        "M 0 0 H 10 V 10 H 0 Z",
+			rawB:        "M 5 5 H 15 V 15 H 5 Z",
+			operation:   (*Path).Union,
+			subpaths:    1,
+			samples: []sample{
+				{Point{2, 2}, true}, {Point{7, 7}, true}, {Point{12, 12}, true},
+				{Point{12, 2}, false}, {Point{2, 12}, false},
+			},
+		},
+		{
+			description: "intersection",
+			rawA:        "M 0 0 H 10 V 10 H 0 Z",
+			rawB:        "M 5 5 H 15 V 15 H 5 Z",
+			operation:   (*Path).Intersection,
+			subpaths:    1,
+			samples: []sample{
+				{Point{7, 7}, true}, {Point{2, 2}, false}, {Point{12, 12}, false},
+			},
+		},
+		{
+			description: "difference",
+			rawA:        "M 0 0 H 10 V 10 H 0 Z",
+			rawB:        "M 5 5 H 15 V 15 H 5 Z",
+			operation:   (*Path).Difference,
+			subpaths:    1,
+			samples: []sample{
+				{Point{2, 2}, true}, {Point{7, 7}, false}, {Point{12, 12}, false},
+			},
+		},
+		{
+			description: "xor",
+			rawA:        "M 0 0 H 10 V 10 H 0 Z",
+			rawB:        "M 5 5 H 15 V 15 H 5 Z",
+			operation:   (*Path).Xor,
+			subpaths:    2,
+			samples: []sample{
+				{Point{2, 2}, true}, {Point{7, 7}, false}, {Point{12, 12}, true},
+			},
+		},
+		{
+			description: "hole",
+			rawA:        "M 0 0 H 10 V 10 H 0 Z",
+			rawB:        "M 3 3 H 7 V 7 H 3 Z",
+			operation:   (*Path).Difference,
+			subpaths:    2,
+			samples:     []sample{{Point{1, 1}, true}, {Point{5, 5}, false}},
+		},
+		{
+			description: "shared edge",
+			rawA:        "M 0 0 H 10 V 10 H 0 Z",
+			rawB:        "M 10 0 H 20 V 10 H 10 Z",
+			operation:   (*Path).Union,
+			subpaths:    1,
+			samples: []sample{
+				{Point{5, 5}, true}, {Point{10, 5}, true}, {Point{15, 5}, true},
+			},
+		},
+		{
+			description: "nonzero",
+			rawA:        "M 0 0 H 10 V 10 H 0 Z M 3 3 H 7 V 7 H 3 Z",
+			rawB:        "M 20 20 H 25 V 25 H 20 Z",
+			rule:        NonZero,
+			operation:   (*Path).Union,
+			subpaths:    2,
+			samples: []sample{
+				{Point{1, 1}, true}, {Point{5, 5}, true}, {Point{22, 22}, true},
+			},
+		},
+		{
+			description: "evenodd",
+			rawA:        "M 0 0 H 10 V 10 H 0 Z M 3 3 H 7 V 7 H 3 Z",
+			rawB:        "M 20 20 H 25 V 25 H 20 Z",
+			rule:        EvenOdd,
+			operation:   (*Path).Union,
+			subpaths:    3,
+			samples: []sample{
+				{Point{1, 1}, true}, {Point{5, 5}, false}, {Point{22, 22}, true},
+			},
+		},
+		{
+			description: "curves",
+			rawA:        "M 0 5 A 5 5 0 0 1 10 5 A 5 5 0 0 1 0 5 Z",
+			rawB:        "M 6 5 A 5 5 0 0 1 16 5 A 5 5 0 0 1 6 5 Z",
+			operation:   (*Path).Intersection,
+			subpaths:    1,
+			samples: []sample{
+				{Point{8, 5}, true}, {Point{8, 1}, false}, {Point{5, 5}, false},
+			},
+		},
+		{
+			description: "empty operand",
+			rawA:        "M 0 0 H 10 V 10 H 0 Z",
+			rawB:        "",
+			operation:   (*Path).Union,
+			subpaths:    1,
+			samples:     []sample{{Point{5, 5}, true}, {Point{15, 5}, false}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			a, err := NewPath(test.rawA)
+			if err != nil {
+				t.Fatalf("Path: unexpected error: %v", err)
+			}
+			b, err := NewPath(test.rawB)
+			if err != nil {
+				t.Fatalf("Path: unexpected error: %v", err)
+			}
+
+			result := test.operation(a, b, test.rule)
+
+			subpaths := result.Subpaths()
+			if len(subpaths) != test.subpaths {
+				t.Errorf("Path: expected %d subpaths, actual %v",
+					test.subpaths, result)
+			}
+
+			for _, sample := range test.samples {
+				actual := evenOddContains(result, sample.point)
+				if actual != sample.inside {
+					t.Errorf("Path: expected %v inside to be %t, actual %t in %v",
+						sample.point, sample.inside, actual, result)
+				}
+			}
+		})
+	}
+}
+
+func TestPathBooleanSimplified(t *testing.T) {
+	a, _ := NewPath("M 0 0 H 10 V 10 H 0 Z")
+	b, _ := NewPath("M 10 0 H 20 V 10 H 10 Z")
+
+	expected := "M 0 0 L 20 0 L 20 10 L 0 10 Z"
+	actual := a.Union(b, NonZero).String()
+	if actual != expected {
+		t.Errorf("Path: expected %s, actual %s", expected, actual)
+	}
+}
+
+// evenOddContains returns true if the point is inside the flattened path by
+// the evenodd rule.
+func evenOddContains(path *Path, p Point) bool {
+	inside := false
+
+	for _, polygon := range path.Flatten(0.01) {
+		for i, from := range polygon {
+			to := polygon[(i+1)%len(polygon)]
+			if (from.Y > p.Y) != (to.Y > p.Y) &&
+				p.X < from.X+(p.Y-from.Y)*(to.X-from.X)/(to.Y-from.Y) {
+				inside = !inside
+			}
+		}
+	}
+
+	return inside
+}
+
+func TestPathBooleanManyPolygons(t *testing.T) {
+	// Bars through the origin, each drawn twice at nearly the same angle,
+	// cross each other many times along nearly coincident edges.
+	var raw strings.Builder
+	for i := 0; i < 12; i++ {
+		for _, angle := range []float64{float64(i) * math.Pi / 12, float64(i)*math.Pi/12 + 1e-7} {
+			sin, cos := math.Sincos(angle)
+			for j, corner := range []Point{{-50, -2}, {50, -2}, {50, 2}, {-50, 2}} {
+				command := "L"
+				if j == 0 {
+					command = "M"
+				}
+				fmt.Fprintf(&raw, "%s %v %v ", command,
+					corner.X*cos-corner.Y*sin, corner.X*sin+corner.Y*cos)
+			}
+			raw.WriteString("Z ")
+		}
+	}
+
+	path, err := NewPath(raw.String())
+	if err != nil {
+		t.Fatalf("Path: unexpected error: %v", err)
+	}
+
+	result := path.Union(&Path{}, NonZero)
+	if len(result.Subpaths()) != 1 {
+		t.Errorf("Path: expected 1 subpath, actual %d", len(result.Subpaths()))
+	}
+
+	for _, sample := range []struct {
+		point  Point
+		inside bool
+	}{
+		{Point{0, 0}, true},
+		{Point{45, 0}, true},
+		{Point{45 * math.Cos(math.Pi/6), 45 * math.Sin(math.Pi/6)}, true},
+		{Point{45 * math.Cos(math.Pi/24), 45 * math.Sin(math.Pi/24)}, false},
+		{Point{60, 0}, false},
+	} {
+		actual := evenOddContains(result, sample.point)
+		if actual != sample.inside {
+			t.Errorf("Path: expected %v inside to be %t, actual %t",
+				sample.point, sample.inside, actual)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package svg
+
+import "math"
+
+// Rect is an axis-aligned rectangle.
+type Rect struct {
+	Min, Max Point
+}
+
+// Width returns the width of the rectangle.
+func (r Rect) Width() float64 {
+	return r.Max.X - r.Min.X
+}
+
+// Height returns the height of the rectangle.
+func (r Rect) Height() float64 {
+	return r.Max.Y - r.Min.Y
+}
+
+// extend grows the rectangle to include the point.
+func (r Rect) extend(p Point) Rect {
+	return Rect{
+		Min: Point{math.Min(r.Min.X, p.X), math.Min(r.Min.Y, p.Y)},
+		Max: Point{math.Max(r.Max.X, p.X), math.Max(r.Max.Y, p.Y)},
+	}
+}
+
+// Bounds computes the smallest axis-aligned rectangle that contains the
+// geometry of the path, including the extrema of curves and arcs. Returns an
+// empty rectangle for a path without commands.
+func (p *Path) Bounds() Rect {
+	var bounds Rect
+	empty := true
+
+	include := func(point Point) {
+		if empty {
+			bounds = Rect{point, point}
+			empty = false
+			return
+		}
+		bounds = bounds.extend(point)
+	}
+
+	for _, subpath := range p.subpaths() {
+		include(subpath.start)
+
+		for _, segment := range subpath.segments {
+			include(segment.point(1))
+			for _, t := range segment.extrema() {
+				include(segment.point(t))
+			}
+		}
+	}
+
+	return bounds
+}
@@ -0,0 +1,101 @@
+package svg_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+func TestPathBounds(t *testing.T) {
+	tests := []struct {
+		description string
+		rawPath     string
+		expected    Rect
+	}{
+		{
+			description: "empty path",
+			rawPath:     "",
+			expected:    Rect{},
+		},
+		{
+			description: "single point",
+			rawPath:     "M 10 20",
+			expected:    Rect{Point{10, 20}, Point{10, 20}},
+		},
+		{
+			description: "lines",
+			rawPath:     "M 10 20 l 30 -40 h -50 v 70 z",
+			expected:    Rect{Point{-10, -20}, Point{40, 50}},
+		},
+		{
+			description: "quadratic curve",
+			rawPath:     "M 0 0 Q 10 20 20 0",
+			expected:    Rect{Point{0, 0}, Point{20, 10}},
+		},
+		{
+			description: "cubic curve",
+			rawPath:     "M 0 0 C 0 -40 40 -40 40 0",
+			expected:    Rect{Point{0, -30}, Point{40, 0}},
+		},
+		{
+			description: "smooth cubic curve",
+			rawPath:     "M 0 0 C 0 -40 40 -40 40 0 S 80 40 80 0",
+			expected:    Rect{Point{0, -30}, Point{80, 30}},
+		},
+		{
+			description: "half circle",
+			rawPath:     "M 0 0 A 10 10 0 0 1 20 0",
+			expected:    Rect{Point{0, -10}, Point{20, 0}},
+		},
+		{
+			description: "large arc",
+			rawPath:     "M 0 0 A 10 10 0 1 0 10 10",
+			expected:    Rect{Point{-10, 0}, Point{10, 20}},
+		},
+		{
+			description: "rotated ellipse",
+			rawPath: "M -14.1421356 -14.1421356 " +
+				"A 20 10 45 0 1 14.1421356 14.1421356 " +
+				"A 20 10 45 0 1 -14.1421356 -14.1421356",
+			expected: Rect{
+				Point{-15.811388, -15.811388},
+				Point{15.811388, 15.811388},
+			},
+		},
+		{
+			description: "multiple subpaths",
+			rawPath:     "M 0 0 L 10 10 Z M -5 50 L -6 -1",
+			expected:    Rect{Point{-6, -1}, Point{10, 50}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			path, err := NewPath(test.rawPath)
+			if err != nil {
+				t.Fatalf("Path: unexpected error: %v", err)
+			}
+
+			actual := path.Bounds()
+			if !rectsClose(actual, test.expected, 1e-3) {
+				t.Errorf("Path: expected %v, actual %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRectSize(t *testing.T) {
+	rect := Rect{Point{-10, 5}, Point{20, 45}}
+
+	if rect.Width() != 30 || rect.Height() != 40 {
+		t.Errorf("Rect: expected 30x40, actual %vx%v", rect.Width(), rect.Height())
+	}
+}
+
+func rectsClose(a, b Rect, tolerance float64) bool {
+	return math.Abs(a.Min.X-b.Min.X) < tolerance &&
+		math.Abs(a.Min.Y-b.Min.Y) < tolerance &&
+		math.Abs(a.Max.X-b.Max.X) < tolerance &&
+		math.Abs(a.Max.Y-b.Max.Y) < tolerance
+}
@@ -0,0 +1,235 @@
+package svg
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// rgba is a color with components between 0 and 1 that are not
+// premultiplied by alpha.
+type rgba struct {
+	r, g, b, a float64
+}
+
+// parsePaint parses the value of a fill or stroke property. Returns false for
+// none and for values that cannot be painted. A paint server reference is
+// replaced by its fallback color, as paint servers are not supported.
+func parsePaint(raw, currentColor string) (rgba, bool) {
+	raw = strings.TrimSpace(raw)
+
+	if strings.HasPrefix(raw, "url(") {
+		end := strings.IndexByte(raw, ')')
+		if end < 0 {
+			return rgba{}, false
+		}
+		raw = strings.TrimSpace(raw[end+1:])
+	}
+
+	switch {
+	case raw == "" || raw == "none":
+		return rgba{}, false
+	case strings.EqualFold(raw, "currentColor"):
+		raw = currentColor
+	}
+
+	return parseColor(raw)
+}
+
+// parseColor parses a CSS color: a keyword, a hexadecimal color or one of the
+// rgb(), rgba(), hsl() and hsla() functions.
+func parseColor(raw string) (rgba, bool) {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+
+	if value, ok := namedColors[raw]; ok {
+		return hexColor(value, 6), true
+	}
+
+	if raw == "transparent" {
+		return rgba{}, true
+	}
+
+	if strings.HasPrefix(raw, "#") {
+		digits := raw[1:]
+		value, err := strconv.ParseUint(digits, 16, 32)
+		if err != nil {
+			return rgba{}, false
+		}
+
+		switch len(digits) {
+		case 3, 4, 6, 8:
+			return hexColor(uint32(value), len(digits)), true
+		}
+		return rgba{}, false
+	}
+
+	open, end := strings.IndexByte(raw, '('), len(raw)-1
+	if open < 0 || raw[end] != ')' {
+		return rgba{}, false
+	}
+
+	name := strings.TrimSpace(raw[:open])
+	args := strings.FieldsFunc(raw[open+1:end], func(r rune) bool {
+		return r == ',' || r == '/' || r == ' ' || r == '\t' || r == '\n'
+	})
+
+	if len(args) != 3 && len(args) != 4 {
+		return rgba{}, false
+	}
+
+	alpha := 1.0
+	if len(args) == 4 {
+		value, ok := parseFraction(args[3], 1)
+		if !ok {
+			return rgba{}, false
+		}
+		alpha = value
+	}
+
+	switch name {
+	case "rgb", "rgba":
+		var components [3]float64
+		for i, arg := range args[:3] {
+			value, ok := parseFraction(arg, 255)
+			if !ok {
+				return rgba{}, false
+			}
+			components[i] = value
+		}
+		return rgba{components[0], components[1], components[2], alpha}, true
+
+	case "hsl", "hsla":
+		hue, err := strconv.ParseFloat(strings.TrimSuffix(args[0], "deg"), 64)
+		saturation, okS := parseFraction(args[1], 100)
+		lightness, okL := parseFraction(args[2], 100)
+		if err != nil || !okS || !okL {
+			return rgba{}, false
+		}
+		r, g, b := hslToRGB(hue, saturation, lightness)
+		return rgba{r, g, b, alpha}, true
+	}
+
+	return rgba{}, false
+}
+
+// parseFraction parses a number, which is divided by scale, or a
+// percentage and returns it clamped between 0 and 1.
+func parseFraction(raw string, scale float64) (float64, bool) {
+	value, err := strconv.ParseFloat(strings.TrimSuffix(raw, "%"), 64)
+	if err != nil {
+		return 0, false
+	}
+
+	if strings.HasSuffix(raw, "%") {
+		value /= 100
+	} else {
+		value /= scale
+	}
+
+	return math.Max(0, math.Min(1, value)), true
+}
+
+// hexColor converts the value of a hexadecimal color with the given number of
+// digits.
+func hexColor(value uint32, digits int) rgba {
+	bits := uint(4)
+	if digits > 4 {
+		bits = 8
+	}
+	max := float64(uint32(1)<<bits - 1)
+
+	component := func(i int) float64 {
+		return float64(value>>(bits*uint(i))&uint32(max)) / max
+	}
+
+	if digits == 4 || digits == 8 {
+		return rgba{component(3), component(2), component(1), component(0)}
+	}
+	return rgba{component(2), component(1), component(0), 1}
+}
+
+// hslToRGB converts a color given by its hue in degrees, saturation and
+// lightness to its red, green and blue components.
+func hslToRGB(hue, saturation, lightness float64) (float64, float64, float64) {
+	hue = math.Mod(math.Mod(hue, 360)+360, 360) / 60
+
+	chroma := (1 - math.Abs(2*lightness-1)) * saturation
+	x := chroma * (1 - math.Abs(math.Mod(hue, 2)-1))
+	m := lightness - chroma/2
+
+	var r, g, b float64
+	switch {
+	case hue < 1:
+		r, g = chroma, x
+	case hue < 2:
+		r, g = x, chroma
+	case hue < 3:
+		g, b = chroma, x
+	case hue < 4:
+		g, b = x, chroma
+	case hue < 5:
+		r, b = x, chroma
+	default:
+		r, b = chroma, x
+	}
+
+	return r + m, g + m, b + m
+}
+
+// namedColors maps the CSS color keywords to their values.
+var namedColors = map[string]uint32{
+	"aliceblue": 0xf0f8ff, "antiquewhite": 0xfaebd7, "aqua": 0x00ffff,
+	"aquamarine": 0x7fffd4, "azure": 0xf0ffff, "beige": 0xf5f5dc,
+	"bisque": 0xffe4c4, "black": 0x000000, "blanchedalmond": 0xffebcd,
+	"blue": 0x0000ff, "blueviolet": 0x8a2be2, "brown": 0xa52a2a,
+	"burlywood": 0xdeb887, "cadetblue": 0x5f9ea0, "chartreuse": 0x7fff00,
+	"chocolate": 0xd2691e, "coral": 0xff7f50, "cornflowerblue": 0x6495ed,
+	"cornsilk": 0xfff8dc, "crimson": 0xdc143c, "cyan": 0x00ffff,
+	"darkblue": 0x00008b, "darkcyan": 0x008b8b, "darkgoldenrod": 0xb8860b,
+	"darkgray": 0xa9a9a9, "darkgreen": 0x006400, "darkgrey": 0xa9a9a9,
+	"darkkhaki": 0xbdb76b, "darkmagenta": 0x8b008b, "darkolivegreen": 0x556b2f,
+	"darkorange": 0xff8c00, "darkorchid": 0x9932cc, "darkred": 0x8b0000,
+	"darksalmon": 0xe9967a, "darkseagreen": 0x8fbc8f, "darkslateblue": 0x483d8b,
+	"darkslategray": 0x2f4f4f, "darkslategrey": 0x2f4f4f,
+	"darkturquoise": 0x00ced1, "darkviolet": 0x9400d3, "deeppink": 0xff1493,
+	"deepskyblue": 0x00bfff, "dimgray": 0x696969, "dimgrey": 0x696969,
+	"dodgerblue": 0x1e90ff, "firebrick": 0xb22222, "floralwhite": 0xfffaf0,
+	"forestgreen": 0x228b22, "fuchsia": 0xff00ff, "gainsboro": 0xdcdcdc,
+	"ghostwhite": 0xf8f8ff, "gold": 0xffd700, "goldenrod": 0xdaa520,
+	"gray": 0x808080, "green": 0x008000, "greenyellow": 0xadff2f,
+	"grey": 0x808080, "honeydew": 0xf0fff0, "hotpink": 0xff69b4,
+	"indianred": 0xcd5c5c, "indigo": 0x4b0082, "ivory": 0xfffff0,
+	"khaki": 0xf0e68c, "lavender": 0xe6e6fa, "lavenderblush": 0xfff0f5,
+	"lawngreen": 0x7cfc00, "lemonchiffon": 0xfffacd, "lightblue": 0xadd8e6,
+	"lightcoral": 0xf08080, "lightcyan": 0xe0ffff,
+	"lightgoldenrodyellow": 0xfafad2, "lightgray": 0xd3d3d3,
+	"lightgreen": 0x90ee90, "lightgrey": 0xd3d3d3, "lightpink": 0xffb6c1,
+	"lightsalmon": 0xffa07a, "lightseagreen": 0x20b2aa,
+	"lightskyblue": 0x87cefa, "lightslategray": 0x778899,
+	"lightslategrey": 0x778899, "lightsteelblue": 0xb0c4de,
+	"lightyellow": 0xffffe0, "lime": 0x00ff00, "limegreen": 0x32cd32,
+	"linen": 0xfaf0e6, "magenta": 0xff00ff, "maroon": 0x800000,
+	"mediumaquamarine": 0x66cdaa, "mediumblue": 0x0000cd,
+	"mediumorchid": 0xba55d3, "mediumpurple": 0x9370db,
+	"mediumseagreen": 0x3cb371, "mediumslateblue": 0x7b68ee,
+	"mediumspringgreen": 0x00fa9a, "mediumturquoise": 0x48d1cc,
+	"mediumvioletred": 0xc71585, "midnightblue": 0x191970,
+	"mintcream": 0xf5fffa, "mistyrose": 0xffe4e1, "moccasin": 0xffe4b5,
+	"navajowhite": 0xffdead, "navy": 0x000080, "oldlace": 0xfdf5e6,
+	"olive": 0x808000, "olivedrab": 0x6b8e23, "orange": 0xffa500,
+	"orangered": 0xff4500, "orchid": 0xda70d6, "palegoldenrod": 0xeee8aa,
+	"palegreen": 0x98fb98, "paleturquoise": 0xafeeee,
+	"palevioletred": 0xdb7093, "papayawhip": 0xffefd5, "peachpuff": 0xffdab9,
+	"peru": 0xcd853f, "pink": 0xffc0cb, "plum": 0xdda0dd,
+	"powderblue": 0xb0e0e6, "purple": 0x800080, "rebeccapurple": 0x663399,
+	"red": 0xff0000, "rosybrown": 0xbc8f8f, "royalblue": 0x4169e1,
+	"saddlebrown": 0x8b4513, "salmon": 0xfa8072, "sandybrown": 0xf4a460,
+	"seagreen": 0x2e8b57, "seashell": 0xfff5ee, "sienna": 0xa0522d,
+	"silver": 0xc0c0c0, "skyblue": 0x87ceeb, "slateblue": 0x6a5acd,
+	"slategray": 0x708090, "slategrey": 0x708090, "snow": 0xfffafa,
+	"springgreen": 0x00ff7f, "steelblue": 0x4682b4, "tan": 0xd2b48c,
+	"teal": 0x008080, "thistle": 0xd8bfd8, "tomato": 0xff6347,
+	"turquoise": 0x40e0d0, "violet": 0xee82ee, "wheat": 0xf5deb3,
+	"white": 0xffffff, "whitesmoke": 0xf5f5f5, "yellow": 0xffff00,
+	"yellowgreen": 0x9acd32,
+}
@@ -0,0 +1,85 @@
+package svg
+
+import "sort"
+
+// maxBisections limits the steps taken to find where a curve crosses a
+// horizontal line, which is more than enough for the precision of float64.
+const maxBisections = 64
+
+// Contains returns true if the point is inside the area the path fills with
+// the rule. Subpaths that are not closed are filled as if they were. Curves
+// and arcs are tested exactly, without approximating them by lines.
+func (p *Path) Contains(x, y float64, rule FillRule) bool {
+	point := Point{x, y}
+	winding := 0
+
+	for _, subpath := range p.subpaths() {
+		end := subpath.start
+		for _, segment := range subpath.segments {
+			winding += segmentWinding(segment, point)
+			end = segment.point(1)
+		}
+
+		if !subpath.closed && end != subpath.start {
+			winding += segmentWinding(line{end, subpath.start}, point)
+		}
+	}
+
+	if rule == EvenOdd {
+		return winding%2 != 0
+	}
+	return winding != 0
+}
+
+// segmentWinding returns the winding number contribution of the segment for
+// a ray cast from the point to the right. The segment is split into pieces
+// that run only up or down, and each piece that the ray crosses counts as 1
+// if it runs down and -1 if it runs up.
+func segmentWinding(s segment, p Point) int {
+	ts := s.extrema()
+	sort.Float64s(ts)
+	ts = append(append([]float64{0}, ts...), 1)
+
+	winding := 0
+	for i := 1; i < len(ts); i++ {
+		t0, t1 := ts[i-1], ts[i]
+		from, to := s.point(t0), s.point(t1)
+
+		direction := 1
+		if from.Y > to.Y {
+			direction = -1
+			from, to, t0, t1 = to, from, t1, t0
+		}
+
+		// Pieces include their top end but not their bottom one, so a ray
+		// through the point where two pieces meet crosses only one of them.
+		if p.Y < from.Y || p.Y >= to.Y {
+			continue
+		}
+
+		if crossingX(s, p.Y, t0, t1) > p.X {
+			winding += direction
+		}
+	}
+
+	return winding
+}
+
+// crossingX returns the horizontal position at which the part of the segment
+// between t0 and t1, which runs down from t0 to t1, crosses the height y.
+func crossingX(s segment, y, t0, t1 float64) float64 {
+	if l, ok := s.(line); ok {
+		return l.from.X + (y-l.from.Y)*(l.to.X-l.from.X)/(l.to.Y-l.from.Y)
+	}
+
+	for i := 0; i < maxBisections; i++ {
+		t := (t0 + t1) / 2
+		if s.point(t).Y < y {
+			t0 = t
+		} else {
+			t1 = t
+		}
+	}
+
+	return s.point((t0 + t1) / 2).X
+}
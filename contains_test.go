@@ -0,0 +1,159 @@
+package svg_test
+
+import (
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+func TestPathContains(t *testing.T) {
+	tests := []struct {
+		description string
+		rawPath     string
+		point       Point
+		rule        FillRule
+		expected    bool
+	}{
+		{
+			description: "inside square",
+			rawPath:     "M 0 0 H 10 V 10 H 0 Z",
+			point:       Point{5, 5},
+			expected:    true,
+		},
+		{
+			description: "outside square",
+			rawPath:     "M 0 0 H 10 V 10 H 0 Z",
+			point:       Point{15, 5},
+			expected:    false,
+		},
+		{
+			description: "ray through vertex",
+			rawPath:     "M 5 0 L 10 5 L 5 10 L 0 5 Z",
+			point:       Point{2, 5},
+			expected:    true,
+		},
+		{
+			description: "ray through vertex outside",
+			rawPath:     "M 5 0 L 10 5 L 5 10 L 0 5 Z",
+			point:       Point{-2, 5},
+			expected:    false,
+		},
+		{
+			description: "open subpath",
+			rawPath:     "M 0 0 H 10 V 10",
+			point:       Point{8, 2},
+			expected:    true,
+		},
+		{
+			description: "open subpath outside",
+			rawPath:     "M 0 0 H 10 V 10",
+			point:       Point{2, 8},
+			expected:    false,
+		},
+		{
+			description: "nonzero with same direction",
+			rawPath:     "M 0 0 H 10 V 10 H 0 Z M 3 3 H 7 V 7 H 3 Z",
+			point:       Point{5, 5},
+			rule:        NonZero,
+			expected:    true,
+		},
+		{
+			description: "evenodd with same direction",
+			rawPath:     "M 0 0 H 10 V 10 H 0 Z M 3 3 H 7 V 7 H 3 Z",
+			point:       Point{5, 5},
+			rule:        EvenOdd,
+			expected:    false,
+		},
+		{
+			description: "nonzero with opposite direction",
+			rawPath:     "M 0 0 H 10 V 10 H 0 Z M 3 3 V 7 H 7 V 3 Z",
+			point:       Point{5, 5},
+			rule:        NonZero,
+			expected:    false,
+		},
+		{
+			description: "inside arc",
+			rawPath:     "M 0 5 A 5 5 0 0 1 10 5 A 5 5 0 0 1 0 5 Z",
+			point:       Point{5, 0.1},
+			expected:    true,
+		},
+		{
+			description: "outside arc",
+			rawPath:     "M 0 5 A 5 5 0 0 1 10 5 A 5 5 0 0 1 0 5 Z",
+			point:       Point{1, 1},
+			expected:    false,
+		},
+		{
+			description: "inside cubic",
+			rawPath:     "M 0 10 C 0 -3.33 10 -3.33 10 10 Z",
+			point:       Point{5, 0.1},
+			expected:    true,
+		},
+		{
+			description: "outside cubic",
+			rawPath:     "M 0 10 C 0 -3.33 10 -3.33 10 10 Z",
+			point:       Point{5, -0.1},
+			expected:    false,
+		},
+		{
+			description: "inside quadratic",
+			rawPath:     "M 0 10 Q 5 -10 10 10 Z",
+			point:       Point{5, 0.1},
+			expected:    true,
+		},
+		{
+			description: "outside quadratic",
+			rawPath:     "M 0 10 Q 5 -10 10 10 Z",
+			point:       Point{5, -0.1},
+			expected:    false,
+		},
+		{
+			description: "empty path",
+			rawPath:     "",
+			point:       Point{0, 0},
+			expected:    false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			path, err := NewPath(test.rawPath)
+			if err != nil {
+				t.Fatalf("Path: unexpected error: %v", err)
+			}
+
+			actual := path.Contains(test.point.X, test.point.Y, test.rule)
+			if actual != test.expected {
+				t.Errorf("Path: expected %t, actual %t", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPathContainsBoolean(t *testing.T) {
+	a, _ := NewPath("M 0 0 H 10 V 10 H 0 Z")
+	b, _ := NewPath("M 3 3 H 7 V 7 H 3 Z")
+	result := a.Difference(b, NonZero)
+
+	tests := []struct {
+		description string
+		point       Point
+		expected    bool
+	}{
+		{"outside", Point{15, 5}, false},
+		{"ring", Point{1, 1}, true},
+		{"hole", Point{5, 5}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			for _, rule := range []FillRule{NonZero, EvenOdd} {
+				actual := result.Contains(test.point.X, test.point.Y, rule)
+				if actual != test.expected {
+					t.Errorf("Path: expected %t, actual %t in %v",
+						test.expected, actual, result)
+				}
+			}
+		})
+	}
+}
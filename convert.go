@@ -0,0 +1,162 @@
+package svg
+
+import "strings"
+
+// ToAbsolute returns a copy of the path in which every command is absolute.
+func (p *Path) ToAbsolute() *Path {
+	result := &Path{}
+	cursor := &cursor{}
+
+	for _, command := range p.Commands {
+		absolute := cursor.absolute(command)
+		result.Commands = append(result.Commands, absolute)
+		cursor.advance(absolute)
+	}
+
+	return result
+}
+
+// ToRelative returns a copy of the path in which every command is relative.
+func (p *Path) ToRelative() *Path {
+	result := &Path{}
+	cursor := &cursor{}
+
+	for _, command := range p.Commands {
+		absolute := cursor.absolute(command)
+		result.Commands = append(result.Commands, cursor.relative(absolute))
+		cursor.advance(absolute)
+	}
+
+	return result
+}
+
+// cursor keeps track of the current point and the start of the current
+// subpath while walking the commands of a path.
+type cursor struct {
+	current Point
+	start   Point
+}
+
+// absolute returns a copy of command in absolute form.
+func (c *cursor) absolute(command *PathCommand) *PathCommand {
+	result := &PathCommand{
+		Symbol: strings.ToUpper(command.Symbol),
+		Params: append([]float64{}, command.Params...),
+	}
+
+	if !command.IsAbsolute() {
+		offset(result, c.current)
+	}
+
+	return result
+}
+
+// relative returns a copy of the absolute command in relative form.
+func (c *cursor) relative(command *PathCommand) *PathCommand {
+	result := &PathCommand{
+		Symbol: strings.ToLower(command.Symbol),
+		Params: append([]float64{}, command.Params...),
+	}
+
+	offset(result, Point{-c.current.X, -c.current.Y})
+
+	return result
+}
+
+// advance moves the cursor to the end point of the absolute command.
+func (c *cursor) advance(command *PathCommand) {
+	params := command.Params
+
+	switch command.Symbol {
+	case "M":
+		c.current = Point{params[0], params[1]}
+		c.start = c.current
+	case "Z":
+		c.current = c.start
+	case "H":
+		c.current.X = params[0]
+	case "V":
+		c.current.Y = params[0]
+	default:
+		c.current = Point{params[len(params)-2], params[len(params)-1]}
+	}
+}
+
+// offset moves all coordinates in the parameters of command by delta.
+func offset(command *PathCommand, delta Point) {
+	params := command.Params
+
+	switch strings.ToLower(command.Symbol) {
+	case "h":
+		params[0] += delta.X
+	case "v":
+		params[0] += delta.Y
+	case "a":
+		params[5] += delta.X
+		params[6] += delta.Y
+	case "z":
+	default:
+		for i := 0; i+1 < len(params); i += 2 {
+			params[i] += delta.X
+			params[i+1] += delta.Y
+		}
+	}
+}
+
+// Normalize returns a copy of the path that consists only of absolute M, L,
+// C, Q, A and Z commands. Horizontal and vertical lines are turned into lines
+// and smooth curves into curves with explicit control points.
+func (p *Path) Normalize() *Path {
+	result := &Path{}
+	cursor := &cursor{}
+	previous := ""
+
+	// control is the last control point of the previous curve.
+	var control Point
+
+	for _, command := range p.Commands {
+		absolute := cursor.absolute(command)
+		params := absolute.Params
+		current := cursor.current
+		normalized := absolute
+
+		switch absolute.Symbol {
+		case "H":
+			normalized = &PathCommand{"L", []float64{params[0], current.Y}}
+		case "V":
+			normalized = &PathCommand{"L", []float64{current.X, params[0]}}
+		case "S":
+			first := current
+			if previous == "C" || previous == "S" {
+				first = reflect(control, current)
+			}
+			normalized = &PathCommand{
+				"C", append([]float64{first.X, first.Y}, params...)}
+		case "T":
+			first := current
+			if previous == "Q" || previous == "T" {
+				first = reflect(control, current)
+			}
+			normalized = &PathCommand{
+				"Q", append([]float64{first.X, first.Y}, params...)}
+		}
+
+		switch normalized.Symbol {
+		case "C":
+			control = Point{normalized.Params[2], normalized.Params[3]}
+		case "Q":
+			control = Point{normalized.Params[0], normalized.Params[1]}
+		}
+
+		result.Commands = append(result.Commands, normalized)
+		previous = absolute.Symbol
+		cursor.advance(absolute)
+	}
+
+	return result
+}
+
+// reflect returns the reflection of point about center.
+func reflect(point, center Point) Point {
+	return Point{2*center.X - point.X, 2*center.Y - point.Y}
+}
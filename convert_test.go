@@ -0,0 +1,171 @@
+package svg_test
+
+import (
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+func TestPathToAbsolute(t *testing.T) {
+	tests := []struct {
+		description string
+		rawPath     string
+		expected    string
+	}{
+		{
+			description: "absolute path",
+			rawPath:     "M 10 20 L 30 40 Z",
+			expected:    "M 10 20 L 30 40 Z",
+		},
+		{
+			description: "relative lines",
+			rawPath:     "m 10 20 l 5 5 h 10 v -10 z",
+			expected:    "M 10 20 L 15 25 H 25 V 15 Z",
+		},
+		{
+			description: "curves",
+			rawPath:     "M 10 10 c 1 2 3 4 5 6 s 1 1 2 2 q 1 0 2 2 t 4 4",
+			expected:    "M 10 10 C 11 12 13 14 15 16 S 16 17 17 18 Q 18 18 19 20 T 23 24",
+		},
+		{
+			description: "arc",
+			rawPath:     "M 10 10 a 5 5 30 1 0 10 -10",
+			expected:    "M 10 10 A 5 5 30 1 0 20 0",
+		},
+		{
+			description: "command after closepath",
+			rawPath:     "M 10 10 l 10 0 z l 0 10 z m 5 5 l 1 1",
+			expected:    "M 10 10 L 20 10 Z L 10 20 Z M 15 15 L 16 16",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			path, err := NewPath(test.rawPath)
+			if err != nil {
+				t.Fatalf("Path: unexpected error: %v", err)
+			}
+
+			if actual := path.ToAbsolute().String(); actual != test.expected {
+				t.Errorf("Path: expected %q, actual %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPathToRelative(t *testing.T) {
+	tests := []struct {
+		description string
+		rawPath     string
+		expected    string
+	}{
+		{
+			description: "relative path",
+			rawPath:     "m 10 20 l 30 40 z",
+			expected:    "m 10 20 l 30 40 z",
+		},
+		{
+			description: "absolute lines",
+			rawPath:     "M 10 20 L 15 25 H 25 V 15 Z",
+			expected:    "m 10 20 l 5 5 h 10 v -10 z",
+		},
+		{
+			description: "curves",
+			rawPath:     "M 10 10 C 11 12 13 14 15 16 S 16 17 17 18 Q 18 18 19 20 T 23 24",
+			expected:    "m 10 10 c 1 2 3 4 5 6 s 1 1 2 2 q 1 0 2 2 t 4 4",
+		},
+		{
+			description: "arc",
+			rawPath:     "M 10 10 A 5 5 30 1 0 20 0",
+			expected:    "m 10 10 a 5 5 30 1 0 10 -10",
+		},
+		{
+			description: "command after closepath",
+			rawPath:     "M 10 10 L 20 10 Z L 10 20 Z M 15 15",
+			expected:    "m 10 10 l 10 0 z l 0 10 z m 5 5",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			path, err := NewPath(test.rawPath)
+			if err != nil {
+				t.Fatalf("Path: unexpected error: %v", err)
+			}
+
+			if actual := path.ToRelative().String(); actual != test.expected {
+				t.Errorf("Path: expected %q, actual %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPathToAbsoluteDoesNotModify(t *testing.T) {
+	path, err := NewPath("m 10 20 l 5 5")
+	if err != nil {
+		t.Fatalf("Path: unexpected error: %v", err)
+	}
+
+	path.ToAbsolute()
+
+	if actual := path.String(); actual != "m 10 20 l 5 5" {
+		t.Errorf("Path: expected path to be unchanged, actual %q", actual)
+	}
+}
+
+func TestPathNormalize(t *testing.T) {
+	tests := []struct {
+		description string
+		rawPath     string
+		expected    string
+	}{
+		{
+			description: "horizontal and vertical lines",
+			rawPath:     "M 10 20 H 30 v 10 h -5 V 0",
+			expected:    "M 10 20 L 30 20 L 30 30 L 25 30 L 25 0",
+		},
+		{
+			description: "smooth cubic after cubic",
+			rawPath:     "M 0 0 C 10 0 20 10 20 20 S 30 40 40 40",
+			expected:    "M 0 0 C 10 0 20 10 20 20 C 20 30 30 40 40 40",
+		},
+		{
+			description: "smooth cubic without previous cubic",
+			rawPath:     "M 0 0 L 10 10 s 10 0 10 10",
+			expected:    "M 0 0 L 10 10 C 10 10 20 10 20 20",
+		},
+		{
+			description: "smooth quadratic chain",
+			rawPath:     "M 0 0 Q 5 10 10 0 T 20 0 t 10 0",
+			expected:    "M 0 0 Q 5 10 10 0 Q 15 -10 20 0 Q 25 10 30 0",
+		},
+		{
+			description: "smooth quadratic after cubic",
+			rawPath:     "M 0 0 C 1 1 2 2 3 3 T 10 10",
+			expected:    "M 0 0 C 1 1 2 2 3 3 Q 3 3 10 10",
+		},
+		{
+			description: "smooth curve at the start of a subpath",
+			rawPath:     "M 0 0 C 1 1 2 2 3 3 Z S 5 5 6 6",
+			expected:    "M 0 0 C 1 1 2 2 3 3 Z C 0 0 5 5 6 6",
+		},
+		{
+			description: "relative arc",
+			rawPath:     "m 10 10 a 5 5 0 0 1 10 0 z",
+			expected:    "M 10 10 A 5 5 0 0 1 20 10 Z",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			path, err := NewPath(test.rawPath)
+			if err != nil {
+				t.Fatalf("Path: unexpected error: %v", err)
+			}
+
+			if actual := path.Normalize().String(); actual != test.expected {
+				t.Errorf("Path: expected %q, actual %q", test.expected, actual)
+			}
+		})
+	}
+}
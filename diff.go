@@ -0,0 +1,390 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// ChangeKind is the kind of a difference found by Diff.
+type ChangeKind int
+
+// Kinds of differences between two element trees.
+const (
+	ElementAdded ChangeKind = iota
+	ElementRemoved
+	ElementMoved
+	AttributeAdded
+	AttributeRemoved
+	AttributeChanged
+	TextChanged
+)
+
+var changeKindNames = map[ChangeKind]string{
+	ElementAdded:     "added element",
+	ElementRemoved:   "removed element",
+	ElementMoved:     "moved element",
+	AttributeAdded:   "added attribute",
+	AttributeRemoved: "removed attribute",
+	AttributeChanged: "changed attribute",
+	TextChanged:      "changed text",
+}
+
+// String returns a description of the kind.
+func (k ChangeKind) String() string {
+	if name, ok := changeKindNames[k]; ok {
+		return name
+	}
+	return "unknown change"
+}
+
+// Change is a single difference between two element trees.
+type Change struct {
+	Kind ChangeKind
+
+	// Path locates the element the change is about, in the second tree or,
+	// for removed elements, in the first one. It lists the names of the
+	// element and its ancestors with the position of each among the
+	// siblings of the same name, e.g. "/svg/g[2]/path[1]".
+	Path string
+
+	// Attribute is the name of the attribute of attribute changes.
+	Attribute string
+
+	// Old and New are the attribute values or texts before and after the
+	// change. For moved elements they are the paths in the first and the
+	// second tree.
+	Old string
+	New string
+}
+
+// String returns a description of the change.
+func (c Change) String() string {
+	switch c.Kind {
+	case ElementAdded, ElementRemoved:
+		return fmt.Sprintf("%s %s", c.Kind, c.Path)
+	case ElementMoved:
+		return fmt.Sprintf("%s %s to %s", c.Kind, c.Old, c.New)
+	case AttributeAdded, AttributeRemoved, AttributeChanged:
+		return fmt.Sprintf("%s %s of %s: %q -> %q",
+			c.Kind, c.Attribute, c.Path, c.Old, c.New)
+	}
+	return fmt.Sprintf("%s of %s: %q -> %q", c.Kind, c.Path, c.Old, c.New)
+}
+
+// DiffOptions configures how DiffWithOptions compares elements.
+type DiffOptions struct {
+	// Tolerance is the largest difference between the numbers of two
+	// attribute values or texts that are otherwise the same for them to be
+	// equal. Zero compares values exactly.
+	Tolerance float64
+
+	// PathData compares the d attributes of paths as path data, so values
+	// that only differ in how they are written are equal.
+	PathData bool
+}
+
+// Diff returns the differences between the element trees a and b, comparing
+// attribute values and texts exactly.
+func Diff(a, b *Element) []Change {
+	return DiffWithOptions(a, b, DiffOptions{})
+}
+
+// DiffWithOptions returns the differences between the element trees a and b:
+// elements that were added, removed or moved among their siblings, and
+// changes to attributes and text. Children are matched by their id
+// attributes first, then by being equal and then by their names, in order.
+// The changes of an element are listed before the changes of its children.
+func DiffWithOptions(a, b *Element, opts DiffOptions) []Change {
+	d := &differ{opts: opts}
+
+	switch {
+	case a == nil && b == nil:
+	case a == nil:
+		d.add(ElementAdded, "/"+b.Name, "", "", "")
+	case b == nil:
+		d.add(ElementRemoved, "/"+a.Name, "", "", "")
+	case a.Name != b.Name:
+		d.add(ElementRemoved, "/"+a.Name, "", "", "")
+		d.add(ElementAdded, "/"+b.Name, "", "", "")
+	default:
+		d.diff(a, b, "/"+a.Name, "/"+b.Name)
+	}
+
+	return d.changes
+}
+
+// differ collects the changes between two trees.
+type differ struct {
+	opts    DiffOptions
+	changes []Change
+}
+
+func (d *differ) add(kind ChangeKind, path, attribute, before, after string) {
+	d.changes = append(d.changes, Change{
+		Kind:      kind,
+		Path:      path,
+		Attribute: attribute,
+		Old:       before,
+		New:       after,
+	})
+}
+
+// diff compares two elements of the same name at the paths in their trees.
+func (d *differ) diff(a, b *Element, pathA, pathB string) {
+	var names []string
+	for name := range a.Attributes {
+		names = append(names, name)
+	}
+	for name := range b.Attributes {
+		if _, ok := a.Attributes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		before, inA := a.Attributes[name]
+		after, inB := b.Attributes[name]
+
+		switch {
+		case !inA:
+			d.add(AttributeAdded, pathB, name, "", after)
+		case !inB:
+			d.add(AttributeRemoved, pathB, name, before, "")
+		case !d.attributeEqual(a.Name, name, before, after):
+			d.add(AttributeChanged, pathB, name, before, after)
+		}
+	}
+
+	if !d.valueEqual(a.Content, b.Content) {
+		d.add(TextChanged, pathB, "", a.Content, b.Content)
+	}
+
+	pairs := d.pair(a.Children, b.Children)
+	childPathsA := childPaths(a.Children, pathA)
+	childPathsB := childPaths(b.Children, pathB)
+
+	for i := range a.Children {
+		if pairs[i] < 0 {
+			d.add(ElementRemoved, childPathsA[i], "", "", "")
+		}
+	}
+
+	moved := movedPairs(pairs)
+	matched := map[int]int{}
+	for i, j := range pairs {
+		if j >= 0 {
+			matched[j] = i
+		}
+	}
+
+	for j, child := range b.Children {
+		i, ok := matched[j]
+		if !ok {
+			d.add(ElementAdded, childPathsB[j], "", "", "")
+			continue
+		}
+
+		if moved[i] {
+			d.add(ElementMoved, childPathsB[j], "", childPathsA[i], childPathsB[j])
+		}
+		d.diff(a.Children[i], child, childPathsA[i], childPathsB[j])
+	}
+}
+
+// pair matches the children of two elements. It returns the index in b of
+// the child matched to each child in a, or -1 if it has none.
+func (d *differ) pair(a, b []*Element) []int {
+	pairs := make([]int, len(a))
+	for i := range pairs {
+		pairs[i] = -1
+	}
+	taken := make([]bool, len(b))
+
+	match := func(same func(x, y *Element) bool) {
+		for i, x := range a {
+			if pairs[i] >= 0 {
+				continue
+			}
+			for j, y := range b {
+				if !taken[j] && x.Name == y.Name && same(x, y) {
+					pairs[i], taken[j] = j, true
+					break
+				}
+			}
+		}
+	}
+
+	match(func(x, y *Element) bool {
+		id, ok := x.Attributes["id"]
+		return ok && y.Attributes["id"] == id
+	})
+	match(func(x, y *Element) bool {
+		_, hasID := x.Attributes["id"]
+		return !hasID && d.equal(x, y)
+	})
+	match(func(x, y *Element) bool {
+		_, hasID := x.Attributes["id"]
+		_, otherHasID := y.Attributes["id"]
+		return !hasID && !otherHasID
+	})
+
+	return pairs
+}
+
+// equal returns true if there are no differences between the elements.
+func (d *differ) equal(a, b *Element) bool {
+	if a.Name != b.Name || len(a.Attributes) != len(b.Attributes) ||
+		len(a.Children) != len(b.Children) || !d.valueEqual(a.Content, b.Content) {
+		return false
+	}
+
+	for name, value := range a.Attributes {
+		other, ok := b.Attributes[name]
+		if !ok || !d.attributeEqual(a.Name, name, value, other) {
+			return false
+		}
+	}
+
+	for i, child := range a.Children {
+		if !d.equal(child, b.Children[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// attributeEqual compares two values of an attribute of an element.
+func (d *differ) attributeEqual(element, name, a, b string) bool {
+	if d.opts.PathData && element == "path" && name == "d" {
+		pathA, errA := NewPath(a)
+		pathB, errB := NewPath(b)
+		if errA == nil && errB == nil {
+			return pathsWithin(pathA, pathB, d.opts.Tolerance)
+		}
+	}
+
+	return d.valueEqual(a, b)
+}
+
+// valueEqual compares two attribute values or texts, allowing the numbers in
+// them to differ by the tolerance.
+func (d *differ) valueEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	if d.opts.Tolerance <= 0 {
+		return false
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		parserA := &valueParser{raw: a, pos: i}
+		parserB := &valueParser{raw: b, pos: j}
+
+		numberA, errA := parserA.number()
+		numberB, errB := parserB.number()
+
+		if errA == nil && errB == nil {
+			if math.Abs(numberA-numberB) > d.opts.Tolerance {
+				return false
+			}
+			i, j = parserA.pos, parserB.pos
+			continue
+		}
+
+		if a[i] != b[j] {
+			return false
+		}
+		i++
+		j++
+	}
+
+	return i == len(a) && j == len(b)
+}
+
+// pathsWithin returns true if the paths have the same commands with
+// parameters that differ by at most the tolerance.
+func pathsWithin(a, b *Path, tolerance float64) bool {
+	if tolerance <= 0 {
+		return a.Equal(b)
+	}
+
+	if len(a.Commands) != len(b.Commands) {
+		return false
+	}
+
+	for i, command := range a.Commands {
+		other := b.Commands[i]
+		if command.Symbol != other.Symbol ||
+			len(command.Params) != len(other.Params) {
+			return false
+		}
+
+		for k, param := range command.Params {
+			if math.Abs(param-other.Params[k]) > tolerance {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// childPaths returns the paths of the children of the element at the path.
+func childPaths(children []*Element, path string) []string {
+	paths := make([]string, len(children))
+	counts := map[string]int{}
+
+	for i, child := range children {
+		counts[child.Name]++
+		paths[i] = path + "/" + child.Name + "[" +
+			strconv.Itoa(counts[child.Name]) + "]"
+	}
+
+	return paths
+}
+
+// movedPairs returns the children of a whose matched children in b are out
+// of order. The longest run of pairs that keeps its order stays in place and
+// all others are moved.
+func movedPairs(pairs []int) map[int]bool {
+	var indices []int
+	for i, j := range pairs {
+		if j >= 0 {
+			indices = append(indices, i)
+		}
+	}
+
+	// lengths[k] is the length of the longest increasing run of pairs ending
+	// with the pair indices[k], and previous[k] the pair before it.
+	lengths := make([]int, len(indices))
+	previous := make([]int, len(indices))
+	best := -1
+
+	for k, i := range indices {
+		lengths[k], previous[k] = 1, -1
+		for l := 0; l < k; l++ {
+			if pairs[indices[l]] < pairs[i] && lengths[l]+1 > lengths[k] {
+				lengths[k], previous[k] = lengths[l]+1, l
+			}
+		}
+		if best < 0 || lengths[k] > lengths[best] {
+			best = k
+		}
+	}
+
+	moved := map[int]bool{}
+	for _, i := range indices {
+		moved[i] = true
+	}
+	for k := best; k >= 0; k = previous[k] {
+		delete(moved, indices[k])
+	}
+
+	return moved
+}
@@ -0,0 +1,136 @@
+package svg_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		description string
+		a           string
+		b           string
+		opts        DiffOptions
+		expected    []string
+	}{
+		{
+			description: "equal",
+			a:           `<svg><g fill="red"><rect/></g></svg>`,
+			b:           `<svg>  <g fill="red"> <rect/> </g></svg>`,
+			expected:    nil,
+		},
+		{
+			description: "attributes",
+			a:           `<svg width="1" height="2"><rect x="1"/></svg>`,
+			b:           `<svg height="3" fill="red"><rect x="1"/></svg>`,
+			expected: []string{
+				`added attribute fill of /svg: "" -> "red"`,
+				`changed attribute height of /svg: "2" -> "3"`,
+				`removed attribute width of /svg: "1" -> ""`,
+			},
+		},
+		{
+			description: "text",
+			a:           `<svg><text>Hello</text></svg>`,
+			b:           `<svg><text>World</text></svg>`,
+			expected: []string{
+				`changed text of /svg/text[1]: "Hello" -> "World"`,
+			},
+		},
+		{
+			description: "added and removed elements",
+			a:           `<svg><rect/><circle/><rect id="a"/></svg>`,
+			b:           `<svg><path/><rect/><circle/></svg>`,
+			expected: []string{
+				"removed element /svg/rect[2]",
+				"added element /svg/path[1]",
+			},
+		},
+		{
+			description: "changed children are matched by name",
+			a:           `<svg><g><rect x="1"/></g><circle/></svg>`,
+			b:           `<svg><g><rect x="2"/></g><circle/></svg>`,
+			expected: []string{
+				`changed attribute x of /svg/g[1]/rect[1]: "1" -> "2"`,
+			},
+		},
+		{
+			description: "reordered children",
+			a:           `<svg><rect id="a"/><rect id="b"/><circle/><path/></svg>`,
+			b:           `<svg><circle/><rect id="b"/><rect id="a" x="1"/><path/></svg>`,
+			expected: []string{
+				"moved element /svg/circle[1] to /svg/circle[1]",
+				"moved element /svg/rect[2] to /svg/rect[1]",
+				`added attribute x of /svg/rect[2]: "" -> "1"`,
+			},
+		},
+		{
+			description: "different roots",
+			a:           `<svg/>`,
+			b:           `<g/>`,
+			expected:    []string{"removed element /svg", "added element /g"},
+		},
+		{
+			description: "tolerance",
+			a:           `<svg><rect x="1" transform="translate(10.0001, 5)"/></svg>`,
+			b:           `<svg><rect x="1.01" transform="translate(10, 5.0)"/></svg>`,
+			opts:        DiffOptions{Tolerance: 0.001},
+			expected: []string{
+				`changed attribute x of /svg/rect[1]: "1" -> "1.01"`,
+			},
+		},
+		{
+			description: "path data",
+			a:           `<svg><path d="M10,20L30,40z"/><path d="M 0 0 L 1 1"/></svg>`,
+			b:           `<svg><path d="M 10 20 L 30 40 z"/><path d="M 0 0 L 1 1.0005"/></svg>`,
+			opts:        DiffOptions{PathData: true},
+			expected: []string{
+				`changed attribute d of /svg/path[2]: "M 0 0 L 1 1" -> "M 0 0 L 1 1.0005"`,
+			},
+		},
+		{
+			description: "path data with tolerance",
+			a:           `<svg><path d="M 0 0 L 1 1"/></svg>`,
+			b:           `<svg><path d="M0 0l1 1.0005"/></svg>`,
+			opts:        DiffOptions{PathData: true, Tolerance: 0.001},
+			expected: []string{
+				`changed attribute d of /svg/path[1]: "M 0 0 L 1 1" -> "M0 0l1 1.0005"`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			changes := DiffWithOptions(parse(t, test.a), parse(t, test.b), test.opts)
+
+			var actual []string
+			for _, change := range changes {
+				actual = append(actual, change.String())
+			}
+
+			if strings.Join(actual, "\n") != strings.Join(test.expected, "\n") {
+				t.Fatalf("Diff: expected %q, actual %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDiffNil(t *testing.T) {
+	element := &Element{Name: "svg"}
+
+	if changes := Diff(nil, nil); len(changes) != 0 {
+		t.Fatalf("Diff: expected no changes, actual %v", changes)
+	}
+
+	changes := Diff(nil, element)
+	if len(changes) != 1 || changes[0].Kind != ElementAdded || changes[0].Path != "/svg" {
+		t.Fatalf("Diff: expected added root, actual %v", changes)
+	}
+
+	changes = Diff(element, nil)
+	if len(changes) != 1 || changes[0].Kind != ElementRemoved || changes[0].Path != "/svg" {
+		t.Fatalf("Diff: expected removed root, actual %v", changes)
+	}
+}
@@ -1,38 +1,132 @@
 package svg
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
+	"unicode/utf8"
 )
 
-// Element is a representation of an SVG element.
+// Namespace names of the namespaces commonly found in SVG documents.
+const (
+	SVGNamespace      = "http://www.w3.org/2000/svg"
+	XLinkNamespace    = "http://www.w3.org/1999/xlink"
+	XMLNamespace      = "http://www.w3.org/XML/1998/namespace"
+	SodipodiNamespace = "http://sodipodi.sourceforge.net/DTD/sodipodi-0.dtd"
+	InkscapeNamespace = "http://www.inkscape.org/namespaces/inkscape"
+)
+
+// knownPrefixes maps the conventional prefixes of common namespaces to their
+// namespace names. Render declares them when they are used but not declared.
+var knownPrefixes = map[string]string{
+	"xlink":    XLinkNamespace,
+	"sodipodi": SodipodiNamespace,
+	"inkscape": InkscapeNamespace,
+}
+
+// Element is a representation of an SVG element. The names of the element and
+// its attributes are qualified names, which keep the namespace prefix they
+// are written with, e.g. "xlink:href". Namespace declarations are kept as
+// attributes named "xmlns" and "xmlns:" followed by the prefix.
+//
+// Content holds the text directly inside the element, joined together when
+// it is interleaved with child elements, and is empty if the text is only
+// whitespace.
 type Element struct {
 	Name       string
 	Attributes map[string]string
 	Children   []*Element
 	Content    string
+
+	// Nodes holds the content of the element in document order: its child
+	// elements together with text, comments, processing instructions and
+	// directives. New fills it so that Render reproduces the source.
+	Nodes []Node
+
+	// Prolog and Epilog hold the nodes before and after the root element of
+	// a document, such as the XML declaration, a DOCTYPE and comments. They
+	// are only used on the root element.
+	Prolog []Node
+	Epilog []Node
+
+	// Source is the span of the element in the source it was decoded from,
+	// from the start of its start tag to the end of its end tag. It is only
+	// recorded by NewWithOptions when asked to.
+	Source *Span
+
+	parent *Element
+
+	// AttributeOrder holds the names of the attributes in the order they are
+	// rendered in. New records the order of the source. Attributes that are
+	// not listed are rendered after the listed ones, sorted by name, so the
+	// output is the same on every run.
+	AttributeOrder []string
+}
+
+// Position is a location in a source. Offset is counted in bytes from 0,
+// Line and Column from 1, with columns in characters.
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+// Span is the part of a source between two positions. End is the position
+// right after the last character of the span.
+type Span struct {
+	Start Position
+	End   Position
+}
+
+// DecodeOptions configures how NewWithOptions decodes a source.
+type DecodeOptions struct {
+	// Positions records the span of every element in its Source field.
+	Positions bool
 }
 
 // New creates an Element instance from an SVG input.
 func New(source io.Reader) (*Element, error) {
-	return decodeFromSource(xml.NewDecoder(source))
+	return NewWithOptions(source, DecodeOptions{})
+}
+
+// NewWithOptions creates an Element instance from an SVG input, decoded
+// according to opts.
+func NewWithOptions(source io.Reader, opts DecodeOptions) (*Element, error) {
+	data, err := io.ReadAll(source)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding element: %w", err)
+	}
+
+	return decodeFromSource(&tokenReader{
+		decoder:   xml.NewDecoder(bytes.NewReader(data)),
+		data:      data,
+		positions: opts.Positions,
+		located:   Position{Line: 1, Column: 1},
+	})
 }
 
-// Render creates an SVG output from the element. Returns an error if the
-// element is empty.
+// Render creates an SVG output from the element, together with its prolog
+// and epilog, exactly as it is held. Returns an error if the element is
+// empty.
 func (e *Element) Render(w io.Writer) error {
-	encoder := xml.NewEncoder(w)
+	return e.RenderWithOptions(w, RenderOptions{})
+}
 
-	if err := encode(e, encoder); err != nil {
-		return fmt.Errorf("Could not render element: %s", err)
-	}
+// SortAttributes makes the element and its descendants render their
+// attributes sorted by name, regardless of the order they were parsed in.
+func (e *Element) SortAttributes() {
+	e.AttributeOrder = nil
 
-	return encoder.Flush()
+	for _, child := range e.Children {
+		child.SortAttributes()
+	}
 }
 
-// Equal checks if two elements are equivalent.
+// Equal checks if two elements are equivalent. The order of attributes is not
+// taken into account.
 func (e *Element) Equal(o *Element) bool {
 	if e.Name != o.Name || e.Content != o.Content ||
 		len(e.Attributes) != len(o.Attributes) ||
@@ -54,27 +148,75 @@ func (e *Element) Equal(o *Element) bool {
 	return true
 }
 
+// Namespaces returns the namespace declarations of the element, mapping each
+// declared prefix to its namespace name. The default namespace is mapped from
+// the empty prefix.
+func (e *Element) Namespaces() map[string]string {
+	namespaces := map[string]string{}
+
+	for name, value := range e.Attributes {
+		switch prefix, local := SplitName(name); {
+		case prefix == "" && local == "xmlns":
+			namespaces[""] = value
+		case prefix == "xmlns":
+			namespaces[local] = value
+		}
+	}
+
+	return namespaces
+}
+
+// SplitName splits a qualified name into its namespace prefix and local name.
+// The prefix is empty for unqualified names.
+func SplitName(name string) (prefix, local string) {
+	if i := strings.Index(name, ":"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
+// qualifiedName joins the prefix and the local part of a raw token name.
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
 // deserialize creates element from decoder token.
 func deserialize(token xml.StartElement) *Element {
 	element := &Element{
-		Name:       token.Name.Local,
+		Name:       qualifiedName(token.Name),
 		Attributes: map[string]string{},
 	}
 
 	for _, attr := range token.Attr {
-		element.Attributes[attr.Name.Local] = attr.Value
+		name := qualifiedName(attr.Name)
+		element.Attributes[name] = attr.Value
+		element.AttributeOrder = append(element.AttributeOrder, name)
 	}
 
 	return element
 }
 
-func serialize(e *Element) xml.StartElement {
-	// TODO: investigate Space attr of Name
+// serialize creates a start token for the element. Qualified names are
+// written as they are, so their prefixes are kept. The namespace declarations
+// are added in front of the attributes of the element.
+func serialize(e *Element, declarations map[string]string) xml.StartElement {
 	var attributes []xml.Attr
-	for name, value := range e.Attributes {
+
+	for _, prefix := range sortedKeys(declarations) {
+		attr := xml.Attr{
+			Name:  xml.Name{Local: "xmlns:" + prefix},
+			Value: declarations[prefix],
+		}
+		attributes = append(attributes, attr)
+	}
+
+	for _, name := range attributeOrder(e) {
 		attr := xml.Attr{
 			Name:  xml.Name{Local: name},
-			Value: value,
+			Value: e.Attributes[name],
 		}
 		attributes = append(attributes, attr)
 	}
@@ -85,38 +227,235 @@ func serialize(e *Element) xml.StartElement {
 	}
 }
 
-// decodeFromSource creates the first element from the decoder.
-func decodeFromSource(decoder *xml.Decoder) (*Element, error) {
+// attributeOrder returns the names of the attributes of the element in the
+// order they are rendered in.
+func attributeOrder(e *Element) []string {
+	var names []string
+	listed := map[string]bool{}
+
+	for _, name := range e.AttributeOrder {
+		if _, ok := e.Attributes[name]; ok && !listed[name] {
+			names = append(names, name)
+			listed[name] = true
+		}
+	}
+
+	var rest []string
+	for name := range e.Attributes {
+		if !listed[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+
+	return append(names, rest...)
+}
+
+// sortedKeys returns the keys of the map in increasing order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// missingDeclarations returns the known namespaces whose prefixes are used in
+// the element or its descendants without being declared on the element that
+// uses them or one of its ancestors.
+func missingDeclarations(e *Element) map[string]string {
+	missing := map[string]string{}
+
+	var collect func(*Element, map[string]bool)
+	collect = func(e *Element, inScope map[string]bool) {
+		// Declarations on the element are added to a copy of those of its
+		// ancestors, so they do not apply to its siblings.
+		declared := inScope
+		copied := false
+		for name := range e.Attributes {
+			prefix, local := SplitName(name)
+			if prefix != "xmlns" {
+				continue
+			}
+
+			if !copied {
+				declared = map[string]bool{}
+				for key := range inScope {
+					declared[key] = true
+				}
+				copied = true
+			}
+			declared[local] = true
+		}
+
+		use := func(prefix string) {
+			if namespace, ok := knownPrefixes[prefix]; ok && !declared[prefix] {
+				missing[prefix] = namespace
+			}
+		}
+
+		prefix, _ := SplitName(e.Name)
+		use(prefix)
+		for name := range e.Attributes {
+			prefix, _ := SplitName(name)
+			use(prefix)
+		}
+
+		for _, child := range e.Children {
+			collect(child, declared)
+		}
+	}
+	collect(e, map[string]bool{})
+
+	return missing
+}
+
+// tokenReader reads raw tokens while keeping the whole input, so that CDATA
+// sections can be told apart from other text and errors can be located.
+type tokenReader struct {
+	decoder *xml.Decoder
+	data    []byte
+
+	// offset is the byte offset of the last token.
+	offset int
+
+	// positions is set if the spans of elements are recorded and located is
+	// the last position that was computed.
+	positions bool
+	located   Position
+}
+
+// token returns the next raw token and, for character data, whether it was
+// written as a CDATA section. Errors other than io.EOF are SyntaxErrors.
+func (r *tokenReader) token() (xml.Token, bool, error) {
+	r.offset = int(r.decoder.InputOffset())
+
+	token, err := r.decoder.RawToken()
+	if err == io.EOF {
+		return nil, false, err
+	} else if err != nil {
+		msg := err.Error()
+		if syntax, ok := err.(*xml.SyntaxError); ok {
+			msg = syntax.Msg
+		}
+		return nil, false, r.errorf(int(r.decoder.InputOffset()), "%s", msg)
+	}
+
+	_, text := token.(xml.CharData)
+	cdata := text && bytes.HasPrefix(r.data[r.offset:], []byte("<![CDATA["))
+
+	return token, cdata, nil
+}
+
+// start records the start of the element at the last token.
+func (r *tokenReader) start(e *Element) {
+	if r.positions {
+		e.Source = &Span{Start: r.position(r.offset)}
+	}
+}
+
+// end records the end of the element after the last token.
+func (r *tokenReader) end(e *Element) {
+	if r.positions {
+		e.Source.End = r.position(int(r.decoder.InputOffset()))
+	}
+}
+
+// position returns the position of the byte offset. Positions are computed
+// from the last one, so offsets must not decrease between calls.
+func (r *tokenReader) position(offset int) Position {
+	for p := &r.located; p.Offset < offset; p.Offset++ {
+		switch c := r.data[p.Offset]; {
+		case c == '\n':
+			p.Line++
+			p.Column = 1
+		case utf8.RuneStart(c):
+			p.Column++
+		}
+	}
+
+	return r.located
+}
+
+// errorf returns a SyntaxError that occurred at the given offset.
+func (r *tokenReader) errorf(offset int, format string,
+	args ...interface{}) error {
+
+	return syntaxError(string(r.data), offset, format, args...)
+}
+
+// toNode converts a token other than a start or end element to a node.
+func toNode(token xml.Token, cdata bool) Node {
+	switch token := token.(type) {
+	case xml.CharData:
+		return Text{Data: string(token), CDATA: cdata}
+	case xml.Comment:
+		return Comment{Data: string(token)}
+	case xml.ProcInst:
+		return ProcInst{Target: token.Target, Inst: string(token.Inst)}
+	case xml.Directive:
+		return Directive{Data: string(token)}
+	}
+	return nil
+}
+
+// decodeFromSource creates the first element from the reader, together with
+// the nodes around it.
+func decodeFromSource(r *tokenReader) (*Element, error) {
 	var root *Element
+	var prolog []Node
 
-	for {
-		token, err := decoder.Token()
+	for root == nil {
+		token, cdata, err := r.token()
 		if token == nil && err == io.EOF {
-			return root, nil
+			return nil, nil
 
 		} else if err != nil {
-			return nil, fmt.Errorf("Error decoding element: %s", err)
+			return nil, fmt.Errorf("Error decoding element: %w", err)
 		}
 
 		if element, found := token.(xml.StartElement); found {
 			root = deserialize(element)
-			break
+			r.start(root)
+		} else if node := toNode(token, cdata); node != nil {
+			prolog = append(prolog, node)
 		}
 	}
 
-	if err := decode(root, decoder); err != nil && err != io.EOF {
-		return nil, fmt.Errorf("Error decoding element: %s", err)
+	if err := decode(root, r); err != nil {
+		return nil, fmt.Errorf("Error decoding element: %w", err)
 	}
+	root.Prolog = prolog
 
-	return root, nil
+	// Anything after a second root element is ignored.
+	for {
+		token, cdata, err := r.token()
+		if token == nil && err == io.EOF {
+			return root, nil
+
+		} else if err != nil {
+			return nil, fmt.Errorf("Error decoding element: %w", err)
+		}
+
+		if _, found := token.(xml.StartElement); found {
+			return root, nil
+		} else if node := toNode(token, cdata); node != nil {
+			root.Epilog = append(root.Epilog, node)
+		}
+	}
 }
 
-// decode decodes the child elements of element.
-func decode(e *Element, decoder *xml.Decoder) error {
+// decode decodes the content of element. The decoder has to read raw tokens
+// to keep the namespace prefixes, so it is up to decode to check that the
+// element is closed by a matching end element.
+func decode(e *Element, r *tokenReader) error {
 	for {
-		token, err := decoder.Token()
+		token, cdata, err := r.token()
 		if token == nil && err == io.EOF {
-			break
+			return r.errorf(len(r.data),
+				"unexpected EOF: element <%s> is not closed", e.Name)
 
 		} else if err != nil {
 			return err
@@ -125,40 +464,28 @@ func decode(e *Element, decoder *xml.Decoder) error {
 		switch element := token.(type) {
 		case xml.StartElement:
 			nextElement := deserialize(element)
-			if err := decode(nextElement, decoder); err != nil {
+			nextElement.parent = e
+			r.start(nextElement)
+			if err := decode(nextElement, r); err != nil {
 				return err
 			}
 
 			e.Children = append(e.Children, nextElement)
-
-		case xml.CharData:
-			data := strings.TrimSpace(string(element))
-			if data != "" {
-				e.Content = string(element)
-			}
+			e.Nodes = append(e.Nodes, nextElement)
 
 		case xml.EndElement:
-			if element.Name.Local == e.Name {
-				return nil
+			if name := qualifiedName(element.Name); name != e.Name {
+				return r.errorf(r.offset, "element <%s> closed by </%s>",
+					e.Name, name)
 			}
-		}
-	}
+			e.Content = textContent(e.Nodes)
+			r.end(e)
+			return nil
 
-	return nil
-}
-
-func encode(e *Element, encoder *xml.Encoder) error {
-	start := serialize(e)
-	if err := encoder.EncodeToken(start); err != nil {
-		return err
-	}
-	end := start.End()
-
-	for _, child := range e.Children {
-		if err := encode(child, encoder); err != nil {
-			return err
+		default:
+			if node := toNode(token, cdata); node != nil {
+				e.Nodes = append(e.Nodes, node)
+			}
 		}
 	}
-
-	return encoder.EncodeToken(end)
 }
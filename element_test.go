@@ -156,7 +156,7 @@ func TestElementNewErrors(t *testing.T) {
 		t.Run(test.description, func(t *testing.T) {
 			actual, err := New(strings.NewReader(test.raw))
 			if actual != nil {
-				t.Fatalf("New: expected element to be nil, actual: %s", actual)
+				t.Fatalf("New: expected element to be nil, actual: %v", actual)
 			}
 
 			if !strings.HasPrefix(err.Error(), test.expectedPrefix) {
@@ -343,3 +343,281 @@ func TestElementEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestElementNewNamespaces(t *testing.T) {
+	raw := `<svg xmlns="http://www.w3.org/2000/svg" ` +
+		`xmlns:xlink="http://www.w3.org/1999/xlink" xml:space="preserve">` +
+		`<sodipodi:namedview xmlns:sodipodi="s" sodipodi:pagecolor="#fff"/>` +
+		`<use href="#a" xlink:href="#b"/></svg>`
+
+	expected := &Element{
+		Name: "svg",
+		Attributes: map[string]string{
+			"xmlns":       SVGNamespace,
+			"xmlns:xlink": XLinkNamespace,
+			"xml:space":   "preserve",
+		},
+		Children: []*Element{
+			{
+				Name: "sodipodi:namedview",
+				Attributes: map[string]string{
+					"xmlns:sodipodi":     "s",
+					"sodipodi:pagecolor": "#fff",
+				},
+			},
+			{
+				Name: "use",
+				Attributes: map[string]string{
+					"href":       "#a",
+					"xlink:href": "#b",
+				},
+			},
+		},
+	}
+
+	actual, err := New(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("New: unexpected error: %s", err)
+	}
+
+	if !expected.Equal(actual) {
+		t.Fatalf("New: expected %v, actual %v", expected, actual)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := actual.Render(buf); err != nil {
+		t.Fatalf("Render: unexpected error: %s", err)
+	}
+
+	rendered, err := New(buf)
+	if err != nil {
+		t.Fatalf("New: unexpected error for rendered element: %s", err)
+	}
+
+	if !expected.Equal(rendered) {
+		t.Fatalf("Render: expected %v, actual %v", expected, rendered)
+	}
+}
+
+func TestElementNewMismatchedEndElement(t *testing.T) {
+	_, err := New(strings.NewReader(`<svg><svg:g></g></svg>`))
+	if err == nil {
+		t.Fatalf("New: expected error, actual nil")
+	}
+
+	expected := "Error decoding element: element <svg:g> closed by </g> at line 1, column 13"
+	if err.Error() != expected {
+		t.Fatalf("New: expected %s, actual %s", expected, err)
+	}
+}
+
+func TestElementNamespaces(t *testing.T) {
+	element := &Element{
+		Name: "svg",
+		Attributes: map[string]string{
+			"xmlns":       SVGNamespace,
+			"xmlns:xlink": XLinkNamespace,
+			"xlink:href":  "#a",
+		},
+	}
+
+	actual := element.Namespaces()
+	if len(actual) != 2 || actual[""] != SVGNamespace ||
+		actual["xlink"] != XLinkNamespace {
+		t.Errorf("Element: expected default and xlink namespaces, actual %v",
+			actual)
+	}
+}
+
+func TestSplitName(t *testing.T) {
+	tests := []struct {
+		name, prefix, local string
+	}{
+		{"href", "", "href"},
+		{"xlink:href", "xlink", "href"},
+		{"xmlns", "", "xmlns"},
+	}
+
+	for _, test := range tests {
+		prefix, local := SplitName(test.name)
+		if prefix != test.prefix || local != test.local {
+			t.Errorf("SplitName: expected %s and %s, actual %s and %s",
+				test.prefix, test.local, prefix, local)
+		}
+	}
+}
+
+func TestElementRenderDeclaresKnownNamespaces(t *testing.T) {
+	element := &Element{
+		Name: "g",
+		Children: []*Element{
+			{Name: "use", Attributes: map[string]string{"xlink:href": "#a"}},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := element.Render(buf); err != nil {
+		t.Fatalf("Render: unexpected error: %s", err)
+	}
+
+	expected := `<g xmlns:xlink="http://www.w3.org/1999/xlink">` +
+		`<use xlink:href="#a"></use></g>`
+	if actual := buf.String(); actual != expected {
+		t.Fatalf("Render: expected %s, actual %s", expected, actual)
+	}
+}
+
+func TestElementRenderDeclaresNamespacesInScope(t *testing.T) {
+	element, err := New(strings.NewReader(
+		`<svg><g xmlns:xlink="http://www.w3.org/1999/xlink"/><use xlink:href="#a"/></svg>`))
+	if err != nil {
+		t.Fatalf("New: unexpected error: %s", err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := element.Render(buf); err != nil {
+		t.Fatalf("Render: unexpected error: %s", err)
+	}
+
+	expected := `<svg xmlns:xlink="http://www.w3.org/1999/xlink">` +
+		`<g xmlns:xlink="http://www.w3.org/1999/xlink"></g><use xlink:href="#a"></use></svg>`
+	if actual := buf.String(); actual != expected {
+		t.Fatalf("Render: expected %s, actual %s", expected, actual)
+	}
+}
+
+func TestElementRenderAttributeOrder(t *testing.T) {
+	tests := []struct {
+		description string
+		element     func() *Element
+		expected    string
+	}{
+		{
+			description: "source order",
+			element: func() *Element {
+				element, _ := New(strings.NewReader(
+					`<svg width="1" viewBox="0 0 1 1" height="1" fill="red"/>`))
+				return element
+			},
+			expected: `<svg width="1" viewBox="0 0 1 1" height="1" fill="red"></svg>`,
+		},
+		{
+			description: "attributes added after parsing",
+			element: func() *Element {
+				element, _ := New(strings.NewReader(`<svg y="1" x="1"/>`))
+				element.Attributes["b"] = "2"
+				element.Attributes["a"] = "3"
+				delete(element.Attributes, "y")
+				return element
+			},
+			expected: `<svg x="1" a="3" b="2"></svg>`,
+		},
+		{
+			description: "sorted attributes of elements built in code",
+			element: func() *Element {
+				return &Element{
+					Name: "rect",
+					Attributes: map[string]string{
+						"y": "1", "x": "2", "width": "3", "height": "4",
+						"fill": "red", "stroke": "blue",
+					},
+				}
+			},
+			expected: `<rect fill="red" height="4" stroke="blue" width="3" ` +
+				`x="2" y="1"></rect>`,
+		},
+		{
+			description: "sorted attributes of parsed elements",
+			element: func() *Element {
+				element, _ := New(strings.NewReader(
+					`<svg y="1" x="1"><g c="1" b="2" a="3"/></svg>`))
+				element.SortAttributes()
+				return element
+			},
+			expected: `<svg x="1" y="1"><g a="3" b="2" c="1"></g></svg>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			element := test.element()
+
+			for i := 0; i < 10; i++ {
+				buf := &bytes.Buffer{}
+				if err := element.Render(buf); err != nil {
+					t.Fatalf("Render: unexpected error: %s", err)
+				}
+
+				if actual := buf.String(); actual != test.expected {
+					t.Fatalf("Render: expected %s, actual %s",
+						test.expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestElementNewWithPositions(t *testing.T) {
+	raw := "<?xml version=\"1.0\"?>\n" +
+		"<svg>\n" +
+		"  <g id=\"é\"><rect/></g>\n" +
+		"  <path d=\"M 0 0\"></path>\n" +
+		"</svg>"
+
+	root, err := NewWithOptions(strings.NewReader(raw), DecodeOptions{Positions: true})
+	if err != nil {
+		t.Fatalf("New: unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		element  *Element
+		expected Span
+		source   string
+	}{
+		{
+			element:  root,
+			expected: Span{Position{22, 2, 1}, Position{85, 5, 7}},
+			source:   raw[22:],
+		},
+		{
+			element:  root.Children[0],
+			expected: Span{Position{30, 3, 3}, Position{52, 3, 24}},
+			source:   `<g id="é"><rect/></g>`,
+		},
+		{
+			element:  root.Children[0].Children[0],
+			expected: Span{Position{41, 3, 13}, Position{48, 3, 20}},
+			source:   `<rect/>`,
+		},
+		{
+			element:  root.Children[1],
+			expected: Span{Position{55, 4, 3}, Position{78, 4, 26}},
+			source:   `<path d="M 0 0"></path>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.element.Name, func(t *testing.T) {
+			if test.element.Source == nil || *test.element.Source != test.expected {
+				t.Fatalf("Source: expected %v, actual %v",
+					test.expected, test.element.Source)
+			}
+
+			source := raw[test.expected.Start.Offset:test.expected.End.Offset]
+			if source != test.source {
+				t.Fatalf("Source: expected %s, actual %s", test.source, source)
+			}
+		})
+	}
+}
+
+func TestElementNewWithoutPositions(t *testing.T) {
+	root, err := New(strings.NewReader("<svg><g/></svg>"))
+	if err != nil {
+		t.Fatalf("New: unexpected error: %s", err)
+	}
+
+	if root.Source != nil || root.Children[0].Source != nil {
+		t.Fatalf("Source: expected nil, actual %v", root.Source)
+	}
+}
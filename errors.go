@@ -0,0 +1,56 @@
+package svg
+
+import "fmt"
+
+// SyntaxError is an error in the syntax of an SVG document or of an attribute
+// value. Offset is the position of the error in bytes from the start of the
+// input, Line and Column are counted from 1, with columns in characters.
+type SyntaxError struct {
+	Msg    string
+	Offset int
+	Line   int
+	Column int
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at line %d, column %d", e.Msg, e.Line, e.Column)
+}
+
+// PathError is an error in path data. Token is the index of the command or
+// parameter the error is about, counted from 0 in the order they are written.
+// The SyntaxError it holds can be found with errors.As.
+type PathError struct {
+	SyntaxError
+	Token int
+}
+
+// Unwrap returns the position of the error as a SyntaxError.
+func (e *PathError) Unwrap() error {
+	return &e.SyntaxError
+}
+
+// syntaxError returns an error at the byte offset of the input.
+func syntaxError(input string, offset int, format string,
+	args ...interface{}) *SyntaxError {
+
+	if offset > len(input) {
+		offset = len(input)
+	}
+
+	line, column := 1, 1
+	for _, r := range input[:offset] {
+		if r == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+
+	return &SyntaxError{
+		Msg:    fmt.Sprintf(format, args...),
+		Offset: offset,
+		Line:   line,
+		Column: column,
+	}
+}
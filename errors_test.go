@@ -0,0 +1,143 @@
+package svg_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+func TestSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		description string
+		parse       func() error
+		expected    SyntaxError
+	}{
+		{
+			description: "unclosed tag",
+			parse: func() error {
+				_, err := New(strings.NewReader("<svg>\n  <g>\n  <rect\n</svg>"))
+				return err
+			},
+			expected: SyntaxError{
+				Msg:    "expected attribute name in element",
+				Offset: 20,
+				Line:   4,
+				Column: 1,
+			},
+		},
+		{
+			description: "mismatched end tag",
+			parse: func() error {
+				_, err := New(strings.NewReader("<svg>\n\t<g></a>\n</svg>"))
+				return err
+			},
+			expected: SyntaxError{
+				Msg:    "element <g> closed by </a>",
+				Offset: 10,
+				Line:   2,
+				Column: 5,
+			},
+		},
+		{
+			description: "unclosed element",
+			parse: func() error {
+				_, err := New(strings.NewReader("<svg>\n<g/>"))
+				return err
+			},
+			expected: SyntaxError{
+				Msg:    "unexpected EOF: element <svg> is not closed",
+				Offset: 10,
+				Line:   2,
+				Column: 5,
+			},
+		},
+		{
+			description: "transform",
+			parse: func() error {
+				_, _, err := ParseTransform("translate(1)\nscale(x)")
+				return err
+			},
+			expected: SyntaxError{
+				Msg:    "Unexpected symbol 'x'",
+				Offset: 19,
+				Line:   2,
+				Column: 7,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := test.parse()
+
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("SyntaxError: expected syntax error, actual %v", err)
+			}
+
+			if *syntaxErr != test.expected {
+				t.Fatalf("SyntaxError: expected %+v, actual %+v",
+					test.expected, *syntaxErr)
+			}
+		})
+	}
+}
+
+func TestPathError(t *testing.T) {
+	tests := []struct {
+		description string
+		raw         string
+		expected    PathError
+	}{
+		{
+			description: "invalid parameter",
+			raw:         "M 10 20\nL 30 4.5e",
+			expected: PathError{
+				SyntaxError: SyntaxError{
+					Msg:    "Invalid parameter syntax",
+					Offset: 13,
+					Line:   2,
+					Column: 6,
+				},
+				Token: 5,
+			},
+		},
+		{
+			description: "unrecognized symbol",
+			raw:         "M 1 2 L 3#",
+			expected: PathError{
+				SyntaxError: SyntaxError{
+					Msg:    "Unrecognized symbol '#'",
+					Offset: 9,
+					Line:   1,
+					Column: 10,
+				},
+				Token: 5,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := NewPath(test.raw)
+
+			var pathErr *PathError
+			if !errors.As(err, &pathErr) {
+				t.Fatalf("PathError: expected path error, actual %v", err)
+			}
+
+			if *pathErr != test.expected {
+				t.Fatalf("PathError: expected %+v, actual %+v",
+					test.expected, *pathErr)
+			}
+
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) || *syntaxErr != test.expected.SyntaxError {
+				t.Fatalf("SyntaxError: expected %+v, actual %+v",
+					test.expected.SyntaxError, syntaxErr)
+			}
+		})
+	}
+}
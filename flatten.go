@@ -0,0 +1,100 @@
+package svg
+
+import "math"
+
+// maxFlattenDepth limits how many times a segment is halved while flattening.
+const maxFlattenDepth = 16
+
+// minFlattenTolerance is the smallest tolerance used for flattening a
+// segment, relative to its size.
+const minFlattenTolerance = 1e-4
+
+// Flatten approximates the path with polylines, one for each subpath as split
+// by Subpaths. Curves and arcs are subdivided until no point of them is
+// farther than tolerance from the polyline. Tolerances below a ten thousandth
+// of the size of a segment, including zero and negative ones, are raised to
+// that. Closed subpaths end with their start point.
+func (p *Path) Flatten(tolerance float64) [][]Point {
+	var result [][]Point
+
+	for _, polyline := range p.polylines(tolerance) {
+		result = append(result, polyline.points)
+	}
+
+	return result
+}
+
+// polyline is a flattened subpath.
+type polyline struct {
+	points []Point
+	closed bool
+}
+
+// polylines flattens the subpaths of the path the same way as Flatten.
+func (p *Path) polylines(tolerance float64) []polyline {
+	var result []polyline
+
+	for _, subpath := range p.subpaths() {
+		points := []Point{subpath.start}
+		for _, segment := range subpath.segments {
+			points = flattenSegment(points, segment, tolerance)
+		}
+		result = append(result, polyline{points: points, closed: subpath.closed})
+	}
+
+	return result
+}
+
+// flattenSegment appends the points of a polyline approximating the segment,
+// except for its start point, to points.
+func flattenSegment(points []Point, s segment, tolerance float64) []Point {
+	if l, ok := s.(line); ok {
+		return append(points, l.to)
+	}
+
+	// Split the segment at least once, and arcs into pieces no larger than a
+	// quarter of the ellipse, so that the samples taken from each piece
+	// cannot all happen to lie on its chord.
+	pieces := 2
+	if a, ok := s.(arcSegment); ok {
+		pieces = int(math.Max(2, math.Ceil(math.Abs(a.Sweep)/(math.Pi/2))))
+	}
+
+	start := s.point(0)
+	size := math.Max(s.point(0.5).sub(start).length(), s.point(1).sub(start).length())
+	if !(tolerance >= size*minFlattenTolerance) {
+		tolerance = size * minFlattenTolerance
+	}
+
+	for i := 0; i < pieces; i++ {
+		t0, t1 := float64(i)/float64(pieces), float64(i+1)/float64(pieces)
+		points = flattenRange(points, s, t0, t1, tolerance, maxFlattenDepth)
+	}
+
+	return points
+}
+
+// flattenRange appends the points approximating the segment between t0 and t1,
+// except for the point at t0, to points.
+func flattenRange(points []Point, s segment, t0, t1, tolerance float64,
+	depth int) []Point {
+
+	from, to := s.point(t0), s.point(t1)
+
+	flat := true
+	for _, fraction := range []float64{0.25, 0.5, 0.75} {
+		sample := s.point(t0 + (t1-t0)*fraction)
+		if distanceToSegment(sample, from, to) > tolerance {
+			flat = false
+			break
+		}
+	}
+
+	if flat || depth == 0 {
+		return append(points, to)
+	}
+
+	middle := (t0 + t1) / 2
+	points = flattenRange(points, s, t0, middle, tolerance, depth-1)
+	return flattenRange(points, s, middle, t1, tolerance, depth-1)
+}
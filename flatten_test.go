@@ -0,0 +1,125 @@
+package svg_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+func TestPathFlatten(t *testing.T) {
+	tests := []struct {
+		description string
+		rawPath     string
+		expected    [][]Point
+	}{
+		{
+			description: "lines",
+			rawPath:     "M 0 0 h 10 v 10",
+			expected:    [][]Point{{{0, 0}, {10, 0}, {10, 10}}},
+		},
+		{
+			description: "closed subpath",
+			rawPath:     "M 0 0 h 10 v 10 z",
+			expected:    [][]Point{{{0, 0}, {10, 0}, {10, 10}, {0, 0}}},
+		},
+		{
+			description: "subpaths",
+			rawPath:     "M 0 0 L 10 10 Z L 5 5 M 20 20",
+			expected: [][]Point{
+				{{0, 0}, {10, 10}, {0, 0}},
+				{{0, 0}, {5, 5}},
+				{{20, 20}},
+			},
+		},
+		{
+			description: "straight curve",
+			rawPath:     "M 0 0 Q 5 5 10 10",
+			expected:    [][]Point{{{0, 0}, {5, 5}, {10, 10}}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			path, err := NewPath(test.rawPath)
+			if err != nil {
+				t.Fatalf("Path: unexpected error: %v", err)
+			}
+
+			actual := path.Flatten(0.1)
+			if len(actual) != len(test.expected) {
+				t.Fatalf("Path: expected %v, actual %v", test.expected, actual)
+			}
+
+			for i, polyline := range test.expected {
+				if len(polyline) != len(actual[i]) {
+					t.Fatalf("Path: expected %v, actual %v",
+						test.expected, actual)
+				}
+
+				for j, point := range polyline {
+					if !pointsClose(point, actual[i][j]) {
+						t.Errorf("Path: expected %v, actual %v",
+							test.expected, actual)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPathFlattenTolerance(t *testing.T) {
+	path, err := NewPath("M 0 0 A 50 50 0 0 1 100 0 A 50 50 0 0 1 0 0 Z")
+	if err != nil {
+		t.Fatalf("Path: unexpected error: %v", err)
+	}
+
+	center := Point{50, 0}
+	previous := 0
+
+	for _, tolerance := range []float64{1, 0.1, 0.01} {
+		polylines := path.Flatten(tolerance)
+		if len(polylines) != 1 {
+			t.Fatalf("Path: expected one polyline, actual %v", len(polylines))
+		}
+
+		points := polylines[0]
+		if len(points) <= previous {
+			t.Errorf("Path: expected more than %v points for tolerance %v, "+
+				"actual %v", previous, tolerance, len(points))
+		}
+		previous = len(points)
+
+		for i := 1; i < len(points); i++ {
+			a, b := points[i-1], points[i]
+
+			// The middle of each chord is the farthest point from the circle.
+			middle := Point{(a.X + b.X) / 2, (a.Y + b.Y) / 2}
+			deviation := 50 - math.Hypot(middle.X-center.X, middle.Y-center.Y)
+			if deviation > tolerance {
+				t.Errorf("Path: expected deviation below %v, actual %v",
+					tolerance, deviation)
+			}
+		}
+	}
+}
+
+func TestPathFlattenZeroTolerance(t *testing.T) {
+	path, err := NewPath("M 0 0 C 0 10 10 10 10 0")
+	if err != nil {
+		t.Fatalf("Path: unexpected error: %v", err)
+	}
+
+	for _, tolerance := range []float64{0, -1, math.NaN()} {
+		polylines := path.Flatten(tolerance)
+		if len(polylines) != 1 || len(polylines[0]) > 1000 {
+			t.Errorf("Path: expected a bounded polyline for tolerance %v, actual %d points",
+				tolerance, len(polylines[0]))
+		}
+
+		end := polylines[0][len(polylines[0])-1]
+		if end != (Point{10, 0}) {
+			t.Errorf("Path: expected end %v, actual %v", Point{10, 0}, end)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package svg
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// FormatOptions controls how path data is written by Path.Format.
+type FormatOptions struct {
+	// Precision is the maximum number of decimal places written for each
+	// parameter, as returned by Decimals. Nil, or a negative number, writes
+	// the shortest representation that parses back to the same number.
+	Precision *int
+
+	// Separator is written between parameters. Defaults to a single space.
+	Separator string
+
+	// Compact omits the space around command symbols and the separator
+	// between parameters wherever the path data grammar allows it.
+	Compact bool
+
+	// ImplicitCommands omits the symbol of a command that repeats the
+	// previous one and of a lineto that follows a moveto.
+	ImplicitCommands bool
+
+	// TrimLeadingZeros drops the zero in front of the decimal point of
+	// numbers between -1 and 1.
+	TrimLeadingZeros bool
+}
+
+// Decimals returns a precision of the given number of decimal places for
+// FormatOptions.
+func Decimals(places int) *int {
+	return &places
+}
+
+// String returns the path data of the path in full precision.
+func (p *Path) String() string {
+	return p.Format(FormatOptions{})
+}
+
+// Format returns the path data of the path written according to opts.
+func (p *Path) Format(opts FormatOptions) string {
+	separator := opts.Separator
+	if separator == "" {
+		separator = " "
+	}
+
+	buf := &bytes.Buffer{}
+	previous := ""
+
+	// last is the most recently written parameter. It is empty when a command
+	// symbol has been written last.
+	last := ""
+
+	for _, command := range p.Commands {
+		if !opts.ImplicitCommands || !isImplicit(previous, command) {
+			if buf.Len() > 0 && !opts.Compact {
+				buf.WriteByte(' ')
+			}
+			buf.WriteString(command.Symbol)
+			last = ""
+		}
+		previous = command.Symbol
+
+		for _, param := range command.Params {
+			number := formatNumber(param, opts.places(), opts.TrimLeadingZeros)
+
+			switch {
+			case last == "" && !opts.Compact:
+				buf.WriteByte(' ')
+			case last != "" && (!opts.Compact || needsSeparator(last, number)):
+				buf.WriteString(separator)
+			}
+
+			buf.WriteString(number)
+			last = number
+		}
+	}
+
+	return buf.String()
+}
+
+// isImplicit returns true if the symbol of command can be left out when it
+// follows a command with the previous symbol.
+func isImplicit(previous string, command *PathCommand) bool {
+	if len(command.Params) == 0 || previous == "" {
+		return false
+	}
+
+	switch command.Symbol {
+	case "M", "m":
+		// A repeated moveto would be read back as a lineto.
+		return false
+	case "L":
+		return previous == "L" || previous == "M"
+	case "l":
+		return previous == "l" || previous == "m"
+	}
+
+	return command.Symbol == previous
+}
+
+// needsSeparator returns true if number cannot directly follow last without
+// being read as a part of it.
+func needsSeparator(last, number string) bool {
+	if strings.HasPrefix(number, "-") {
+		return false
+	}
+
+	if strings.HasPrefix(number, ".") && strings.Contains(last, ".") {
+		return false
+	}
+
+	return true
+}
+
+// places returns the number of decimal places of the precision of opts,
+// or -1 for as many as necessary.
+func (opts FormatOptions) places() int {
+	if opts.Precision == nil {
+		return -1
+	}
+	return *opts.Precision
+}
+
+// formatNumber writes value with at most precision decimal places, without
+// trailing zeros. A negative precision uses as many places as necessary.
+func formatNumber(value float64, precision int, trimLeadingZero bool) string {
+	number := strconv.FormatFloat(value, 'f', precision, 64)
+
+	if strings.Contains(number, ".") {
+		number = strings.TrimRight(number, "0")
+		number = strings.TrimSuffix(number, ".")
+	}
+
+	if number == "-0" {
+		number = "0"
+	}
+
+	if trimLeadingZero {
+		switch {
+		case strings.HasPrefix(number, "0."):
+			number = number[1:]
+		case strings.HasPrefix(number, "-0."):
+			number = "-" + number[2:]
+		}
+	}
+
+	return number
+}
@@ -0,0 +1,148 @@
+package svg_test
+
+import (
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+func TestPathString(t *testing.T) {
+	tests := []struct {
+		description string
+		rawPath     string
+		expected    string
+	}{
+		{
+			description: "simple path",
+			rawPath:     "M 10,20 L 30,30 Z",
+			expected:    "M 10 20 L 30 30 Z",
+		},
+		{
+			description: "decimal and negative values",
+			rawPath:     "M.2.3l-1.5-20e-1z",
+			expected:    "M 0.2 0.3 l -1.5 -2 z",
+		},
+		{
+			description: "implicit lineto commands",
+			rawPath:     "m 10,20 30,40 50,60",
+			expected:    "m 10 20 l 30 40 l 50 60",
+		},
+		{
+			description: "arc",
+			rawPath:     "M 0 0 A 25 25 -30 0 1 50 -25",
+			expected:    "M 0 0 A 25 25 -30 0 1 50 -25",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			path, err := NewPath(test.rawPath)
+			if err != nil {
+				t.Fatalf("Path: unexpected error: %v", err)
+			}
+
+			if actual := path.String(); actual != test.expected {
+				t.Errorf("Path: expected %q, actual %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPathFormat(t *testing.T) {
+	tests := []struct {
+		description string
+		rawPath     string
+		options     FormatOptions
+		expected    string
+	}{
+		{
+			description: "precision",
+			rawPath:     "M 1.23456 -0.0001 L 2.5 3.999",
+			options:     FormatOptions{Precision: Decimals(2)},
+			expected:    "M 1.23 0 L 2.5 4",
+		},
+		{
+			description: "zero precision",
+			rawPath:     "M 1.5 -0.25 L 2.75 3",
+			options:     FormatOptions{Precision: Decimals(0)},
+			expected:    "M 2 0 L 3 3",
+		},
+		{
+			description: "default precision",
+			rawPath:     "M 1.23456789 -0.0001 L 2.5 3.999",
+			options:     FormatOptions{},
+			expected:    "M 1.23456789 -0.0001 L 2.5 3.999",
+		},
+		{
+			description: "separator",
+			rawPath:     "M 10 20 L 30 40",
+			options:     FormatOptions{Separator: ","},
+			expected:    "M 10,20 L 30,40",
+		},
+		{
+			description: "implicit commands",
+			rawPath:     "M 10 20 L 30 40 L 50 60 M 1 2 M 3 4 c 1 2 3 4 5 6 c 1 2 3 4 5 6 Z",
+			options:     FormatOptions{ImplicitCommands: true},
+			expected:    "M 10 20 30 40 50 60 M 1 2 M 3 4 c 1 2 3 4 5 6 1 2 3 4 5 6 Z",
+		},
+		{
+			description: "trim leading zeros",
+			rawPath:     "M 0.5 -0.25 L 1.5 0",
+			options:     FormatOptions{TrimLeadingZeros: true},
+			expected:    "M .5 -.25 L 1.5 0",
+		},
+		{
+			description: "compact",
+			rawPath:     "M 10 -20 L 0.5 0.25 L 30 40 Z M 1 2",
+			options: FormatOptions{
+				Compact:          true,
+				ImplicitCommands: true,
+				TrimLeadingZeros: true,
+			},
+			expected: "M10-20 .5.25 30 40ZM1 2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			path, err := NewPath(test.rawPath)
+			if err != nil {
+				t.Fatalf("Path: unexpected error: %v", err)
+			}
+
+			if actual := path.Format(test.options); actual != test.expected {
+				t.Errorf("Path: expected %q, actual %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPathFormatRoundTrip(t *testing.T) {
+	raw := "M 10,20 30,40 Z m -.5.5 c 1 2 3 4 5 6 s -1 -2 -3 -4 " +
+		"q 1 1 2 2 t 3 3 h -10 v 0.125 a 25 25 -30 0 1 50 -25 z"
+
+	path, err := NewPath(raw)
+	if err != nil {
+		t.Fatalf("Path: unexpected error: %v", err)
+	}
+
+	options := []FormatOptions{
+		{},
+		{Compact: true, ImplicitCommands: true},
+		{Compact: true, TrimLeadingZeros: true},
+		{Separator: ",", ImplicitCommands: true},
+	}
+
+	for _, opts := range options {
+		formatted := path.Format(opts)
+
+		actual, err := NewPath(formatted)
+		if err != nil {
+			t.Fatalf("Path: unexpected error for %q: %v", formatted, err)
+		}
+
+		if !path.Equal(actual) {
+			t.Errorf("Path: expected %v, actual %v (%q)", path, actual, formatted)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package svg
+
+import "math"
+
+// Point is a location in the user coordinate system.
+type Point struct {
+	X, Y float64
+}
+
+func (p Point) add(o Point) Point {
+	return Point{p.X + o.X, p.Y + o.Y}
+}
+
+func (p Point) sub(o Point) Point {
+	return Point{p.X - o.X, p.Y - o.Y}
+}
+
+func (p Point) mul(factor float64) Point {
+	return Point{p.X * factor, p.Y * factor}
+}
+
+// lerp interpolates linearly between p and o.
+func (p Point) lerp(o Point, t float64) Point {
+	return Point{p.X + (o.X-p.X)*t, p.Y + (o.Y-p.Y)*t}
+}
+
+func (p Point) length() float64 {
+	return math.Hypot(p.X, p.Y)
+}
+
+func (p Point) dot(o Point) float64 {
+	return p.X*o.X + p.Y*o.Y
+}
+
+// distanceToSegment returns the distance from p to the line segment between a
+// and b.
+func distanceToSegment(p, a, b Point) float64 {
+	direction := b.sub(a)
+
+	squared := direction.dot(direction)
+	if squared == 0 {
+		return p.sub(a).length()
+	}
+
+	t := math.Max(0, math.Min(1, p.sub(a).dot(direction)/squared))
+	return p.sub(a.add(direction.mul(t))).length()
+}
@@ -0,0 +1,3 @@
+module github.com/catiepg/svg
+
+go 1.23
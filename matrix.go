@@ -0,0 +1,184 @@
+package svg
+
+import "math"
+
+// Matrix is an affine transformation in the form used by the transform
+// attribute. It maps a point (x, y) to (A*x + C*y + E, B*x + D*y + F).
+type Matrix struct {
+	A, B, C, D, E, F float64
+}
+
+// Identity is the transformation that leaves every point in place.
+var Identity = Matrix{A: 1, D: 1}
+
+// Translate returns a matrix that moves points by tx and ty.
+func Translate(tx, ty float64) Matrix {
+	return Matrix{A: 1, D: 1, E: tx, F: ty}
+}
+
+// Scale returns a matrix that scales points by sx and sy.
+func Scale(sx, sy float64) Matrix {
+	return Matrix{A: sx, D: sy}
+}
+
+// Rotate returns a matrix that rotates points about the origin by the given
+// angle in degrees.
+func Rotate(angle float64) Matrix {
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+	return Matrix{A: cos, B: sin, C: -sin, D: cos}
+}
+
+// SkewX returns a matrix that skews along the x-axis by the given angle in
+// degrees.
+func SkewX(angle float64) Matrix {
+	return Matrix{A: 1, C: math.Tan(angle * math.Pi / 180), D: 1}
+}
+
+// SkewY returns a matrix that skews along the y-axis by the given angle in
+// degrees.
+func SkewY(angle float64) Matrix {
+	return Matrix{A: 1, B: math.Tan(angle * math.Pi / 180), D: 1}
+}
+
+// Multiply returns the matrix that applies o first and m after it, the same
+// way a transform list applies its rightmost transformation first.
+func (m Matrix) Multiply(o Matrix) Matrix {
+	return Matrix{
+		A: m.A*o.A + m.C*o.B,
+		B: m.B*o.A + m.D*o.B,
+		C: m.A*o.C + m.C*o.D,
+		D: m.B*o.C + m.D*o.D,
+		E: m.A*o.E + m.C*o.F + m.E,
+		F: m.B*o.E + m.D*o.F + m.F,
+	}
+}
+
+// Apply transforms the point.
+func (m Matrix) Apply(p Point) Point {
+	return Point{
+		X: m.A*p.X + m.C*p.Y + m.E,
+		Y: m.B*p.X + m.D*p.Y + m.F,
+	}
+}
+
+// Determinant returns the determinant of the linear part of the matrix.
+func (m Matrix) Determinant() float64 {
+	return m.A*m.D - m.B*m.C
+}
+
+// Invert returns the inverse of the matrix. Returns false if the matrix is not
+// invertible.
+func (m Matrix) Invert() (Matrix, bool) {
+	det := m.Determinant()
+	if det == 0 {
+		return Matrix{}, false
+	}
+
+	return Matrix{
+		A: m.D / det,
+		B: -m.B / det,
+		C: -m.C / det,
+		D: m.A / det,
+		E: (m.C*m.F - m.D*m.E) / det,
+		F: (m.B*m.E - m.A*m.F) / det,
+	}, true
+}
+
+// IsIdentity returns true if the matrix leaves every point in place.
+func (m Matrix) IsIdentity() bool {
+	return m == Identity
+}
+
+// Transform returns a copy of the path with the matrix applied to all of its
+// commands. The result consists of absolute commands. Horizontal and vertical
+// lines that are no longer axis-aligned become lines and the radii, rotation
+// and sweep direction of arcs are adjusted to the transformed ellipse.
+func (p *Path) Transform(m Matrix) *Path {
+	result := &Path{}
+	cursor := &cursor{}
+
+	for _, command := range p.Commands {
+		absolute := cursor.absolute(command)
+		result.Commands = append(result.Commands,
+			transformCommand(absolute, cursor.current, m))
+		cursor.advance(absolute)
+	}
+
+	return result
+}
+
+// transformCommand applies the matrix to an absolute command that starts at
+// the point current.
+func transformCommand(command *PathCommand, current Point,
+	m Matrix) *PathCommand {
+
+	params := command.Params
+
+	switch command.Symbol {
+	case "H":
+		to := m.Apply(Point{params[0], current.Y})
+		if m.B == 0 {
+			return &PathCommand{"H", []float64{to.X}}
+		}
+		return &PathCommand{"L", []float64{to.X, to.Y}}
+
+	case "V":
+		to := m.Apply(Point{current.X, params[0]})
+		if m.C == 0 {
+			return &PathCommand{"V", []float64{to.Y}}
+		}
+		return &PathCommand{"L", []float64{to.X, to.Y}}
+
+	case "A":
+		to := m.Apply(Point{params[5], params[6]})
+		if params[0] == 0 || params[1] == 0 {
+			return &PathCommand{"L", []float64{to.X, to.Y}}
+		}
+
+		rx, ry, rotation := transformEllipse(
+			params[0], params[1], params[2], m)
+
+		sweep := params[4]
+		if m.Determinant() < 0 {
+			sweep = 1 - sweep
+		}
+
+		return &PathCommand{"A", []float64{
+			rx, ry, rotation, params[3], sweep, to.X, to.Y}}
+	}
+
+	result := &PathCommand{Symbol: command.Symbol}
+	for i := 0; i+1 < len(params); i += 2 {
+		point := m.Apply(Point{params[i], params[i+1]})
+		result.Params = append(result.Params, point.X, point.Y)
+	}
+
+	return result
+}
+
+// transformEllipse returns the radii and the rotation in degrees of the
+// ellipse with radii rx and ry rotated by the given angle in degrees, after
+// the linear part of the matrix has been applied to it.
+func transformEllipse(rx, ry, rotation float64, m Matrix) (float64, float64,
+	float64) {
+
+	// The ellipse is the image of the unit circle under the matrix
+	// m * rotate(rotation) * scale(rx, ry).
+	e := Matrix{A: m.A, B: m.B, C: m.C, D: m.D}.
+		Multiply(Rotate(rotation)).Multiply(Scale(rx, ry))
+
+	// Its axes are the eigenvectors of e * e^T and its radii the square roots
+	// of the corresponding eigenvalues.
+	s11 := e.A*e.A + e.C*e.C
+	s12 := e.A*e.B + e.C*e.D
+	s22 := e.B*e.B + e.D*e.D
+
+	mean := (s11 + s22) / 2
+	radius := math.Hypot((s11-s22)/2, s12)
+
+	major := math.Sqrt(mean + radius)
+	minor := math.Sqrt(math.Max(0, mean-radius))
+	angle := math.Atan2(2*s12, s11-s22) / 2 * 180 / math.Pi
+
+	return major, minor, angle
+}
@@ -0,0 +1,168 @@
+package svg_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+func TestMatrixApply(t *testing.T) {
+	tests := []struct {
+		description string
+		matrix      Matrix
+		point       Point
+		expected    Point
+	}{
+		{"identity", Identity, Point{3, 4}, Point{3, 4}},
+		{"translate", Translate(10, -5), Point{3, 4}, Point{13, -1}},
+		{"scale", Scale(2, 3), Point{3, 4}, Point{6, 12}},
+		{"rotate", Rotate(90), Point{3, 4}, Point{-4, 3}},
+		{"skewX", SkewX(45), Point{3, 4}, Point{7, 4}},
+		{"skewY", SkewY(45), Point{3, 4}, Point{3, 7}},
+		{
+			description: "translate after rotate",
+			matrix:      Translate(10, 0).Multiply(Rotate(90)),
+			point:       Point{3, 4},
+			expected:    Point{6, 3},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			actual := test.matrix.Apply(test.point)
+			if !pointsClose(actual, test.expected) {
+				t.Errorf("Matrix: expected %v, actual %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMatrixInvert(t *testing.T) {
+	matrix := Translate(10, 20).Multiply(Rotate(30)).Multiply(Scale(2, -3))
+
+	inverse, ok := matrix.Invert()
+	if !ok {
+		t.Fatalf("Matrix: expected matrix to be invertible")
+	}
+
+	point := Point{7, -11}
+	if actual := inverse.Apply(matrix.Apply(point)); !pointsClose(actual, point) {
+		t.Errorf("Matrix: expected %v, actual %v", point, actual)
+	}
+
+	if _, ok := Scale(0, 1).Invert(); ok {
+		t.Errorf("Matrix: expected singular matrix not to be invertible")
+	}
+}
+
+func TestPathTransform(t *testing.T) {
+	tests := []struct {
+		description string
+		rawPath     string
+		matrix      Matrix
+		expected    string
+	}{
+		{
+			description: "translate relative path",
+			rawPath:     "m 10 10 l 10 0 c 1 1 2 2 3 3 z",
+			matrix:      Translate(5, 5),
+			expected:    "M 15 15 L 25 15 C 26 16 27 17 28 18 Z",
+		},
+		{
+			description: "scale keeps horizontal and vertical lines",
+			rawPath:     "M 10 10 H 20 V 30",
+			matrix:      Scale(2, 3),
+			expected:    "M 20 30 H 40 V 90",
+		},
+		{
+			description: "rotate turns horizontal and vertical lines into lines",
+			rawPath:     "M 10 10 H 20 V 30",
+			matrix:      Rotate(90),
+			expected:    "M -10 10 L -10 20 L -30 20",
+		},
+		{
+			description: "skew keeps horizontal lines",
+			rawPath:     "M 0 10 H 20 V 30",
+			matrix:      SkewX(45),
+			expected:    "M 10 10 H 30 L 50 30",
+		},
+		{
+			description: "non-uniform scale of circular arc",
+			rawPath:     "M 0 0 A 10 10 0 0 1 20 0",
+			matrix:      Scale(2, 1),
+			expected:    "M 0 0 A 20 10 0 0 1 40 0",
+		},
+		{
+			description: "rotated ellipse",
+			rawPath:     "M 0 0 A 20 10 0 1 0 40 0",
+			matrix:      Rotate(30),
+			expected:    "M 0 0 A 20 10 30 1 0 34.641016 20",
+		},
+		{
+			description: "reflection flips sweep",
+			rawPath:     "M 0 0 A 20 10 0 1 0 40 0",
+			matrix:      Scale(1, -1),
+			expected:    "M 0 0 A 20 10 0 1 1 40 0",
+		},
+		{
+			description: "degenerate arc becomes line",
+			rawPath:     "M 0 0 A 0 10 0 1 0 40 0",
+			matrix:      Scale(2, 2),
+			expected:    "M 0 0 L 80 0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			path, err := NewPath(test.rawPath)
+			if err != nil {
+				t.Fatalf("Path: unexpected error: %v", err)
+			}
+
+			actual := path.Transform(test.matrix).Format(FormatOptions{Precision: Decimals(6)})
+			if actual != test.expected {
+				t.Errorf("Path: expected %q, actual %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPathTransformGeometry(t *testing.T) {
+	path, err := NewPath("M 10 20 A 30 10 20 1 1 50 40 Q 60 0 80 10 " +
+		"t 10 10 s 5 5 10 -20 h 5 v -10 z")
+	if err != nil {
+		t.Fatalf("Path: unexpected error: %v", err)
+	}
+
+	matrices := []Matrix{
+		Rotate(30),
+		Scale(2, 0.5).Multiply(Rotate(-45)),
+		SkewX(30).Multiply(Translate(5, 5)),
+		Scale(-1, 1).Multiply(SkewY(-20)),
+	}
+
+	for _, matrix := range matrices {
+		// The transformed path must draw the transformed points of the
+		// original one, which have the same bounds.
+		expected := Rect{}
+		for i, polyline := range path.Flatten(0.001) {
+			for j, point := range polyline {
+				point = matrix.Apply(point)
+				if i == 0 && j == 0 {
+					expected = Rect{point, point}
+				}
+				expected.Min.X = math.Min(expected.Min.X, point.X)
+				expected.Min.Y = math.Min(expected.Min.Y, point.Y)
+				expected.Max.X = math.Max(expected.Max.X, point.X)
+				expected.Max.Y = math.Max(expected.Max.Y, point.Y)
+			}
+		}
+
+		actual := path.Transform(matrix).Bounds()
+		if !rectsClose(actual, expected, 0.01) {
+			t.Errorf("Path: expected bounds %v, actual %v for %v",
+				expected, actual, matrix)
+		}
+	}
+}
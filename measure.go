@@ -0,0 +1,251 @@
+package svg
+
+import (
+	"math"
+	"sort"
+)
+
+// Length returns the total length of the path, the sum of the lengths of all
+// of its subpaths. Closepath commands contribute the line they draw back to
+// the start of the subpath.
+func (p *Path) Length() float64 {
+	return p.Measure().Length()
+}
+
+// PointAt returns the point at the given distance along the path. Distances
+// outside of the path are clamped to its start and end. Use Measure for
+// repeated queries on the same path.
+func (p *Path) PointAt(distance float64) Point {
+	return p.Measure().PointAt(distance)
+}
+
+// TangentAt returns the unit vector in the direction of the path at the given
+// distance along it. Distances outside of the path are clamped to its start
+// and end. Returns the zero vector for a path that draws nothing. Use Measure
+// for repeated queries on the same path.
+func (p *Path) TangentAt(distance float64) Point {
+	return p.Measure().TangentAt(distance)
+}
+
+// MeasuredPath is a path with the lengths of its segments computed, for
+// queries of points along it that do not measure the whole path each time.
+// It does not change with the path it was measured from.
+type MeasuredPath struct {
+	segments []segment
+
+	// ends holds the distance from the start of the path to the end of each
+	// segment.
+	ends []float64
+
+	// start is the start point of the path, used when it has no segments.
+	start Point
+}
+
+// Measure computes the lengths of the segments of the path.
+func (p *Path) Measure() *MeasuredPath {
+	m := &MeasuredPath{}
+	subpaths := p.subpaths()
+
+	if len(subpaths) > 0 {
+		m.start = subpaths[0].start
+	}
+
+	total := 0.0
+	for _, subpath := range subpaths {
+		for _, segment := range subpath.segments {
+			total += segmentLength(segment, 0, 1)
+			m.segments = append(m.segments, segment)
+			m.ends = append(m.ends, total)
+		}
+	}
+
+	return m
+}
+
+// Length returns the total length of the path.
+func (m *MeasuredPath) Length() float64 {
+	if len(m.ends) == 0 {
+		return 0
+	}
+	return m.ends[len(m.ends)-1]
+}
+
+// PointAt returns the point at the given distance along the path, as
+// Path.PointAt does.
+func (m *MeasuredPath) PointAt(distance float64) Point {
+	segment, t, ok := m.locate(distance)
+	if !ok {
+		return m.start
+	}
+
+	return segment.point(t)
+}
+
+// TangentAt returns the direction of the path at the given distance along
+// it, as Path.TangentAt does.
+func (m *MeasuredPath) TangentAt(distance float64) Point {
+	segment, t, ok := m.locate(distance)
+	if !ok {
+		return Point{}
+	}
+
+	return tangent(segment, t)
+}
+
+// locate finds the segment of the path and its parameter at the given
+// distance from the start of the path. Returns false if the path has no
+// segments.
+func (m *MeasuredPath) locate(distance float64) (segment, float64, bool) {
+	if len(m.segments) == 0 {
+		return nil, 0, false
+	}
+
+	i := sort.SearchFloat64s(m.ends, distance)
+	if i == len(m.ends) {
+		return m.segments[i-1], 1, true
+	}
+
+	start := 0.0
+	if i > 0 {
+		start = m.ends[i-1]
+	}
+
+	distance = math.Max(distance-start, 0)
+	return m.segments[i], segmentParameter(m.segments[i], distance, m.ends[i]-start), true
+}
+
+// segmentLength returns the length of the segment between t0 and t1.
+func segmentLength(s segment, t0, t1 float64) float64 {
+	if l, ok := s.(line); ok {
+		return l.to.sub(l.from).length() * (t1 - t0)
+	}
+
+	speed := func(t float64) float64 {
+		return s.derivative(t).length()
+	}
+
+	return integrate(speed, t0, t1)
+}
+
+// segmentParameter returns the parameter of the segment at the given distance
+// from its start. The total length of the segment must be given. Each step
+// only integrates the part of the segment between the previous estimate and
+// the next one.
+func segmentParameter(s segment, distance, length float64) float64 {
+	if length == 0 {
+		return 0
+	}
+
+	low, high := 0.0, 1.0
+	t := distance / length
+	if _, ok := s.(line); ok {
+		return t
+	}
+
+	// measured is the length of the segment up to t.
+	measured := segmentLength(s, 0, t)
+
+	for i := 0; i < 64; i++ {
+		difference := measured - distance
+		if math.Abs(difference) <= lengthTolerance*math.Max(1, length) {
+			break
+		}
+
+		if difference > 0 {
+			high = t
+		} else {
+			low = t
+		}
+
+		// Take a Newton step if it stays within the bracket and bisect
+		// otherwise.
+		next := (low + high) / 2
+		if speed := s.derivative(t).length(); speed > 0 {
+			if newton := t - difference/speed; newton > low && newton < high {
+				next = newton
+			}
+		}
+
+		measured += segmentLength(s, t, next)
+		t = next
+	}
+
+	return t
+}
+
+// tangent returns the unit vector in the direction of the segment at t.
+func tangent(s segment, t float64) Point {
+	direction := s.derivative(t)
+
+	// The derivative of a curve vanishes where a control point coincides
+	// with an end point, so fall back to a nearby chord.
+	if direction.length() < 1e-12 {
+		if t < 0.5 {
+			direction = s.point(t + 1e-6).sub(s.point(t))
+		} else {
+			direction = s.point(t).sub(s.point(t - 1e-6))
+		}
+	}
+
+	length := direction.length()
+	if length == 0 {
+		return Point{}
+	}
+
+	return direction.mul(1 / length)
+}
+
+const (
+	// lengthTolerance is the relative precision of numeric integration.
+	lengthTolerance = 1e-10
+
+	// maxIntegrationDepth limits the recursion of adaptive integration.
+	maxIntegrationDepth = 20
+)
+
+// integrate computes the integral of f over [a, b] with adaptive Gauss-Legendre
+// quadrature.
+func integrate(f func(float64) float64, a, b float64) float64 {
+	whole := gaussLegendre(f, a, b)
+	tolerance := lengthTolerance * math.Max(1, math.Abs(whole))
+
+	return integrateAdaptive(f, a, b, whole, tolerance, maxIntegrationDepth)
+}
+
+func integrateAdaptive(f func(float64) float64, a, b, whole, tolerance float64,
+	depth int) float64 {
+
+	middle := (a + b) / 2
+	left, right := gaussLegendre(f, a, middle), gaussLegendre(f, middle, b)
+
+	if depth == 0 || math.Abs(left+right-whole) <= tolerance {
+		return left + right
+	}
+
+	return integrateAdaptive(f, a, middle, left, tolerance/2, depth-1) +
+		integrateAdaptive(f, middle, b, right, tolerance/2, depth-1)
+}
+
+// Nodes and weights of the five point Gauss-Legendre quadrature rule.
+var (
+	gaussNodes = [5]float64{
+		0, -0.5384693101056831, 0.5384693101056831,
+		-0.9061798459386640, 0.9061798459386640,
+	}
+	gaussWeights = [5]float64{
+		0.5688888888888889, 0.4786286704993665, 0.4786286704993665,
+		0.2369268850561891, 0.2369268850561891,
+	}
+)
+
+// gaussLegendre approximates the integral of f over [a, b].
+func gaussLegendre(f func(float64) float64, a, b float64) float64 {
+	half, middle := (b-a)/2, (a+b)/2
+
+	sum := 0.0
+	for i, node := range gaussNodes {
+		sum += gaussWeights[i] * f(middle+half*node)
+	}
+
+	return sum * half
+}
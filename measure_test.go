@@ -0,0 +1,213 @@
+package svg_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+func TestPathLength(t *testing.T) {
+	tests := []struct {
+		description string
+		rawPath     string
+		expected    float64
+	}{
+		{
+			description: "empty path",
+			rawPath:     "",
+			expected:    0,
+		},
+		{
+			description: "closed square",
+			rawPath:     "M 0 0 h 10 v 10 h -10 z",
+			expected:    40,
+		},
+		{
+			description: "multiple subpaths",
+			rawPath:     "M 0 0 L 3 4 M 100 100 l 0 5",
+			expected:    10,
+		},
+		{
+			description: "straight cubic curve",
+			rawPath:     "M 0 0 C 1 1 2 2 3 3",
+			expected:    3 * math.Sqrt2,
+		},
+		{
+			description: "parabola",
+			rawPath:     "M -1 1 Q 0 -1 1 1",
+			expected:    math.Sqrt(5) + math.Asinh(2)/2,
+		},
+		{
+			description: "circle",
+			rawPath:     "M 0 0 A 10 10 0 0 1 20 0 A 10 10 0 0 1 0 0",
+			expected:    20 * math.Pi,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			path, err := NewPath(test.rawPath)
+			if err != nil {
+				t.Fatalf("Path: unexpected error: %v", err)
+			}
+
+			if actual := path.Length(); math.Abs(actual-test.expected) > 1e-6 {
+				t.Errorf("Path: expected %v, actual %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPathPointAt(t *testing.T) {
+	tests := []struct {
+		description string
+		rawPath     string
+		distance    float64
+		expected    Point
+	}{
+		{
+			description: "start of path",
+			rawPath:     "M 5 5 h 10 v 10",
+			distance:    0,
+			expected:    Point{5, 5},
+		},
+		{
+			description: "negative distance",
+			rawPath:     "M 5 5 h 10 v 10",
+			distance:    -10,
+			expected:    Point{5, 5},
+		},
+		{
+			description: "second segment",
+			rawPath:     "M 5 5 h 10 v 10",
+			distance:    15,
+			expected:    Point{15, 10},
+		},
+		{
+			description: "beyond end of path",
+			rawPath:     "M 5 5 h 10 v 10",
+			distance:    100,
+			expected:    Point{15, 15},
+		},
+		{
+			description: "closing line",
+			rawPath:     "M 0 0 h 10 v 10 z",
+			distance:    25,
+			expected:    Point{10 - 5/math.Sqrt2, 10 - 5/math.Sqrt2},
+		},
+		{
+			description: "quarter of circle",
+			rawPath:     "M 0 0 A 10 10 0 0 1 20 0 A 10 10 0 0 1 0 0",
+			distance:    5 * math.Pi,
+			expected:    Point{10, -10},
+		},
+		{
+			description: "middle of cubic curve with uneven speed",
+			rawPath:     "M 0 0 C 0 0 0 0 10 0",
+			distance:    5,
+			expected:    Point{5, 0},
+		},
+		{
+			description: "only moveto",
+			rawPath:     "M 3 4",
+			distance:    1,
+			expected:    Point{3, 4},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			path, err := NewPath(test.rawPath)
+			if err != nil {
+				t.Fatalf("Path: unexpected error: %v", err)
+			}
+
+			actual := path.PointAt(test.distance)
+			if math.Abs(actual.X-test.expected.X) > 1e-6 ||
+				math.Abs(actual.Y-test.expected.Y) > 1e-6 {
+				t.Errorf("Path: expected %v, actual %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPathTangentAt(t *testing.T) {
+	tests := []struct {
+		description string
+		rawPath     string
+		distance    float64
+		expected    Point
+	}{
+		{
+			description: "line",
+			rawPath:     "M 0 0 l 10 10",
+			distance:    5,
+			expected:    Point{1 / math.Sqrt2, 1 / math.Sqrt2},
+		},
+		{
+			description: "top of circle",
+			rawPath:     "M 0 0 A 10 10 0 0 1 20 0 A 10 10 0 0 1 0 0",
+			distance:    5 * math.Pi,
+			expected:    Point{1, 0},
+		},
+		{
+			description: "start of curve with coincident control point",
+			rawPath:     "M 0 0 C 0 0 10 10 10 20",
+			distance:    0,
+			expected:    Point{1 / math.Sqrt2, 1 / math.Sqrt2},
+		},
+		{
+			description: "empty path",
+			rawPath:     "M 0 0",
+			distance:    0,
+			expected:    Point{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			path, err := NewPath(test.rawPath)
+			if err != nil {
+				t.Fatalf("Path: unexpected error: %v", err)
+			}
+
+			actual := path.TangentAt(test.distance)
+			if math.Abs(actual.X-test.expected.X) > 1e-4 ||
+				math.Abs(actual.Y-test.expected.Y) > 1e-4 {
+				t.Errorf("Path: expected %v, actual %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMeasuredPath(t *testing.T) {
+	path, err := NewPath("M 0 0 A 10 10 0 0 1 20 0 A 10 10 0 0 1 0 0 M 5 5 h 10")
+	if err != nil {
+		t.Fatalf("Path: unexpected error: %v", err)
+	}
+
+	measured := path.Measure()
+
+	if expected, actual := 20*math.Pi+10, measured.Length(); math.Abs(actual-expected) > 1e-6 {
+		t.Errorf("Path: expected %v, actual %v", expected, actual)
+	}
+
+	for distance := -5.0; distance <= 80; distance += 2.5 {
+		expected, actual := path.PointAt(distance), measured.PointAt(distance)
+		if math.Abs(actual.X-expected.X) > 1e-9 || math.Abs(actual.Y-expected.Y) > 1e-9 {
+			t.Errorf("Path: expected %v at %v, actual %v", expected, distance, actual)
+		}
+
+		expected, actual = path.TangentAt(distance), measured.TangentAt(distance)
+		if math.Abs(actual.X-expected.X) > 1e-9 || math.Abs(actual.Y-expected.Y) > 1e-9 {
+			t.Errorf("Path: expected tangent %v at %v, actual %v",
+				expected, distance, actual)
+		}
+	}
+
+	if actual := measured.PointAt(10 * math.Pi); math.Abs(actual.X-20) > 1e-6 ||
+		math.Abs(actual.Y) > 1e-6 {
+		t.Errorf("Path: expected %v, actual %v", Point{20, 0}, actual)
+	}
+}
@@ -0,0 +1,102 @@
+package svg
+
+import "strings"
+
+// Node is a part of the content of an SVG document. It is an *Element, Text,
+// Comment, ProcInst or Directive.
+type Node interface {
+	node()
+}
+
+// Text is character data. Text with CDATA set is written as a CDATA section.
+type Text struct {
+	Data  string
+	CDATA bool
+}
+
+// Comment is an XML comment without its <!-- and --> delimiters.
+type Comment struct {
+	Data string
+}
+
+// ProcInst is a processing instruction, such as the XML declaration with the
+// target "xml".
+type ProcInst struct {
+	Target string
+	Inst   string
+}
+
+// Directive is a directive without its <! and > delimiters, such as a DOCTYPE
+// declaration.
+type Directive struct {
+	Data string
+}
+
+func (*Element) node()  {}
+func (Text) node()      {}
+func (Comment) node()   {}
+func (ProcInst) node()  {}
+func (Directive) node() {}
+
+// textContent returns the text of the nodes joined together, or an empty
+// string if it is only whitespace.
+func textContent(nodes []Node) string {
+	var parts []string
+	for _, node := range nodes {
+		if text, ok := node.(Text); ok {
+			parts = append(parts, text.Data)
+		}
+	}
+
+	content := strings.Join(parts, "")
+	if strings.TrimSpace(content) == "" {
+		return ""
+	}
+	return content
+}
+
+// content returns the nodes rendered inside the element. Elements built in
+// code without nodes render their Content followed by their children. The
+// children stay the authoritative list of child elements: child elements that
+// were removed from Children are left out, the remaining ones take the places
+// of the child elements in Nodes in the order of Children, and any others are
+// added at the end. If Content no longer matches the text of the nodes, the
+// text is replaced by Content.
+func content(e *Element) []Node {
+	var nodes []Node
+
+	replaceText := e.Nodes == nil || textContent(e.Nodes) != e.Content
+	if replaceText && e.Content != "" {
+		nodes = append(nodes, Text{Data: e.Content})
+	}
+
+	children := map[*Element]bool{}
+	for _, child := range e.Children {
+		children[child] = true
+	}
+
+	next := 0
+	for _, node := range e.Nodes {
+		switch node := node.(type) {
+		case *Element:
+			if children[node] && next < len(e.Children) {
+				nodes = append(nodes, e.Children[next])
+				next++
+			}
+
+		case Text:
+			if !replaceText {
+				nodes = append(nodes, node)
+			}
+
+		default:
+			nodes = append(nodes, node)
+		}
+	}
+
+	for _, child := range e.Children[next:] {
+		nodes = append(nodes, child)
+	}
+
+	return nodes
+}
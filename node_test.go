@@ -0,0 +1,192 @@
+package svg_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+func TestElementNewMixedContent(t *testing.T) {
+	element, err := New(strings.NewReader(
+		`<text>Hello <tspan>big</tspan><!-- note --> world</text>`))
+	if err != nil {
+		t.Fatalf("New: unexpected error: %s", err)
+	}
+
+	if expected := "Hello  world"; element.Content != expected {
+		t.Fatalf("Content: expected %q, actual %q", expected, element.Content)
+	}
+
+	expected := []Node{
+		Text{Data: "Hello "},
+		element.Children[0],
+		Comment{Data: " note "},
+		Text{Data: " world"},
+	}
+	if !reflect.DeepEqual(element.Nodes, expected) {
+		t.Fatalf("Nodes: expected %v, actual %v", expected, element.Nodes)
+	}
+}
+
+func TestElementNewCDATA(t *testing.T) {
+	element, err := New(strings.NewReader(
+		`<style>a<![CDATA[rect > a { fill: red }]]>b</style>`))
+	if err != nil {
+		t.Fatalf("New: unexpected error: %s", err)
+	}
+
+	expected := []Node{
+		Text{Data: "a"},
+		Text{Data: "rect > a { fill: red }", CDATA: true},
+		Text{Data: "b"},
+	}
+	if !reflect.DeepEqual(element.Nodes, expected) {
+		t.Fatalf("Nodes: expected %v, actual %v", expected, element.Nodes)
+	}
+}
+
+func TestElementNewPrologAndEpilog(t *testing.T) {
+	element, err := New(strings.NewReader(
+		`<?xml version="1.0"?><!DOCTYPE svg><svg/><!-- end -->`))
+	if err != nil {
+		t.Fatalf("New: unexpected error: %s", err)
+	}
+
+	prolog := []Node{
+		ProcInst{Target: "xml", Inst: `version="1.0"`},
+		Directive{Data: "DOCTYPE svg"},
+	}
+	if !reflect.DeepEqual(element.Prolog, prolog) {
+		t.Fatalf("Prolog: expected %v, actual %v", prolog, element.Prolog)
+	}
+
+	epilog := []Node{Comment{Data: " end "}}
+	if !reflect.DeepEqual(element.Epilog, epilog) {
+		t.Fatalf("Epilog: expected %v, actual %v", epilog, element.Epilog)
+	}
+}
+
+func TestElementRenderNodes(t *testing.T) {
+	tests := []struct {
+		description string
+		raw         string
+	}{
+		{
+			description: "mixed content",
+			raw:         `<text>Hello <tspan>big</tspan> world</text>`,
+		},
+		{
+			description: "document",
+			raw: "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+				"<!DOCTYPE svg PUBLIC \"-//W3C//DTD SVG 1.1//EN\" " +
+				"\"http://www.w3.org/Graphics/SVG/1.1/DTD/svg11.dtd\">\n" +
+				"<!-- Generator: test -->\n" +
+				"<svg>\n" +
+				"\t<style><![CDATA[rect > a { fill: red }]]></style>\n" +
+				"\t<!-- comment -->\n" +
+				"\t<text>1 &lt; 2 &amp; 3</text>\n" +
+				"</svg>\n" +
+				"<!-- trailing -->\n",
+		},
+		{
+			description: "CDATA containing its end delimiter",
+			raw:         `<style><![CDATA[a]]]]><![CDATA[>b]]></style>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			element, err := New(strings.NewReader(test.raw))
+			if err != nil {
+				t.Fatalf("New: unexpected error: %s", err)
+			}
+
+			buf := &bytes.Buffer{}
+			if err := element.Render(buf); err != nil {
+				t.Fatalf("Render: unexpected error: %s", err)
+			}
+
+			if actual := buf.String(); actual != test.raw {
+				t.Fatalf("Render: expected %s, actual %s", test.raw, actual)
+			}
+		})
+	}
+}
+
+func TestElementRenderModifiedNodes(t *testing.T) {
+	tests := []struct {
+		description string
+		modify      func(*Element)
+		expected    string
+	}{
+		{
+			description: "removed child",
+			modify: func(e *Element) {
+				e.Children = e.Children[1:]
+			},
+			expected: `<g>a<!--c-->b<rect></rect>d</g>`,
+		},
+		{
+			description: "reordered children",
+			modify: func(e *Element) {
+				e.Children[0], e.Children[1] = e.Children[1], e.Children[0]
+			},
+			expected: `<g>a<rect></rect><!--c-->b<circle></circle>d</g>`,
+		},
+		{
+			description: "added child",
+			modify: func(e *Element) {
+				e.Children = append(e.Children, &Element{Name: "path"})
+			},
+			expected: `<g>a<circle></circle><!--c-->b<rect></rect>d<path></path></g>`,
+		},
+		{
+			description: "changed content",
+			modify: func(e *Element) {
+				e.Content = "text"
+			},
+			expected: `<g>text<circle></circle><!--c--><rect></rect></g>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			element, err := New(strings.NewReader(
+				`<g>a<circle/><!--c-->b<rect/>d</g>`))
+			if err != nil {
+				t.Fatalf("New: unexpected error: %s", err)
+			}
+			test.modify(element)
+
+			buf := &bytes.Buffer{}
+			if err := element.Render(buf); err != nil {
+				t.Fatalf("Render: unexpected error: %s", err)
+			}
+
+			if actual := buf.String(); actual != test.expected {
+				t.Fatalf("Render: expected %s, actual %s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestElementRenderContent(t *testing.T) {
+	element := &Element{
+		Name:     "text",
+		Content:  "a < b",
+		Children: []*Element{{Name: "tspan", Content: "c"}},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := element.Render(buf); err != nil {
+		t.Fatalf("Render: unexpected error: %s", err)
+	}
+
+	expected := `<text>a &lt; b<tspan>c</tspan></text>`
+	if actual := buf.String(); actual != expected {
+		t.Fatalf("Render: expected %s, actual %s", expected, actual)
+	}
+}
@@ -1,7 +1,6 @@
 package svg
 
 import (
-	"fmt"
 	"strconv"
 	"strings"
 	"unicode"
@@ -125,8 +124,8 @@ func commands(raw string) ([]*PathCommand, error) {
 	// From specification, a path data attribute is invalid if it does not
 	// start with moveto command.
 	if len(tokens) > 0 && strings.ToLower(tokens[0].value) != startCommand {
-		return nil, fmt.Errorf(
-			"Path data does not start with a moveto command: %s", raw)
+		return nil, tokens.errorf(raw, 0,
+			"Path data does not start with a moveto command")
 	}
 
 	operands := []float64{}
@@ -137,7 +136,7 @@ func commands(raw string) ([]*PathCommand, error) {
 		if !tokens[i].operator {
 			number, err := strconv.ParseFloat(value, 64)
 			if err != nil {
-				return nil, fmt.Errorf("Invalid parameter syntax")
+				return nil, tokens.errorf(raw, i, "Invalid parameter syntax")
 			}
 			operands = append(operands, number)
 			continue
@@ -145,7 +144,7 @@ func commands(raw string) ([]*PathCommand, error) {
 
 		paramCount, ok := commandParams[strings.ToLower(value)]
 		if !ok {
-			return nil, fmt.Errorf("Invalid command '%s'", value)
+			return nil, tokens.errorf(raw, i, "Invalid command '%s'", value)
 		}
 
 		operandCount := len(operands)
@@ -156,7 +155,8 @@ func commands(raw string) ([]*PathCommand, error) {
 		}
 
 		if paramCount == 0 || operandCount%paramCount != 0 {
-			return nil, fmt.Errorf("Incorrect number of parameters for %v", value)
+			return nil, tokens.errorf(raw, i,
+				"Incorrect number of parameters for %v", value)
 		}
 
 		loopCount := operandCount / paramCount
@@ -177,10 +177,12 @@ func commands(raw string) ([]*PathCommand, error) {
 	return cmds, nil
 }
 
-// token can contain an operator or an operand as string.
+// token can contain an operator or an operand as string, together with its
+// byte offset in the path data.
 type token struct {
 	value    string
 	operator bool
+	offset   int
 }
 
 // tokens is a collection of tokens
@@ -188,35 +190,51 @@ type tokens []token
 
 // add appends a token if the value is non-empty.
 // Returns true if a new token has been added.
-func (ts *tokens) add(value []rune, operator bool) bool {
+func (ts *tokens) add(value []rune, operator bool, offset int) bool {
 	if len(value) == 0 {
 		return false
 	}
 
-	*ts = append(*ts, token{string(value), operator})
+	*ts = append(*ts, token{string(value), operator, offset})
 
 	return true
 }
 
+// errorf returns an error about the token at the index.
+func (ts tokens) errorf(raw string, index int, format string,
+	args ...interface{}) error {
+
+	return &PathError{
+		SyntaxError: *syntaxError(raw, ts[index].offset, format, args...),
+		Token:       index,
+	}
+}
+
 // tokenize takes value of path data attribute and transforms it into a slice of
 // tokens than represent operators and operands.
 func tokenize(raw string) (*tokens, error) {
 	ts := &tokens{}
 
 	var operand []rune
-	for _, r := range raw {
+	var start int
+	for i, r := range raw {
 		switch {
 		case r == '.':
 			if len(operand) == 0 {
 				operand = append(operand, '0')
+				start = i
 			}
 			if contains(operand, '.') {
-				ts.add(operand, false)
+				ts.add(operand, false, start)
 				operand = []rune{'0'}
+				start = i
 			}
 			fallthrough
 
 		case r >= '0' && r <= '9' || r == 'e':
+			if len(operand) == 0 {
+				start = i
+			}
 			operand = append(operand, r)
 
 		case r == '-':
@@ -224,27 +242,36 @@ func tokenize(raw string) (*tokens, error) {
 				operand = append(operand, r)
 				continue
 			}
-			ts.add(operand, false)
+			ts.add(operand, false, start)
 			operand = []rune{r}
+			start = i
 
 		case (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
-			if ok := ts.add(operand, false); ok {
+			if ok := ts.add(operand, false, start); ok {
 				operand = []rune{}
 			}
-			ts.add([]rune{r}, true)
+			ts.add([]rune{r}, true, i)
 			continue
 
 		case unicode.IsSpace(r) || r == ',':
-			if ok := ts.add(operand, false); ok {
+			if ok := ts.add(operand, false, start); ok {
 				operand = []rune{}
 			}
 
 		default:
-			return nil, fmt.Errorf("Unrecognized symbol '%s'", string(r))
+			index := len(*ts)
+			if len(operand) > 0 {
+				index++
+			}
+			return nil, &PathError{
+				SyntaxError: *syntaxError(raw, i,
+					"Unrecognized symbol '%s'", string(r)),
+				Token: index,
+			}
 		}
 	}
 
-	ts.add(operand, false)
+	ts.add(operand, false, start)
 
 	return ts, nil
 }
@@ -254,27 +254,27 @@ func TestPathErrors(t *testing.T) {
 		{
 			description:   "invalid command",
 			rawPath:       "M 10 20 x",
-			expectedError: "Invalid command 'x'",
+			expectedError: "Invalid command 'x' at line 1, column 9",
 		},
 		{
 			description:   "no moveto command at beginnning",
 			rawPath:       "10,20",
-			expectedError: "Path data does not start with a moveto command: 10,20",
+			expectedError: "Path data does not start with a moveto command at line 1, column 1",
 		},
 		{
 			description:   "incorrect number of parameters",
 			rawPath:       "M 10 20 30 Z",
-			expectedError: "Incorrect number of parameters for M",
+			expectedError: "Incorrect number of parameters for M at line 1, column 1",
 		},
 		{
 			description:   "parameter not a number",
 			rawPath:       "M 10 7%4 Z",
-			expectedError: "Unrecognized symbol '%'",
+			expectedError: "Unrecognized symbol '%' at line 1, column 7",
 		},
 		{
 			description:   "parameter not a number",
 			rawPath:       "M 10--1 Z",
-			expectedError: "Invalid parameter syntax",
+			expectedError: "Invalid parameter syntax at line 1, column 5",
 		},
 	}
 
@@ -0,0 +1,603 @@
+package svg
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FillRule is the rule that decides which points are inside a shape, as set
+// by the fill-rule property.
+type FillRule int
+
+// Rules for the inside of a shape.
+const (
+	NonZero FillRule = iota
+	EvenOdd
+)
+
+// subsamples is the number of sub-scanlines sampled for each row of pixels.
+const subsamples = 16
+
+// Rasterize renders the element tree into an image of the given size. The
+// document is scaled to fit the image, keeping its aspect ratio. Paths and
+// basic shapes are filled and stroked with solid colors, taking into account
+// the fill and stroke properties, opacity, transforms and viewBox. Text,
+// images, use elements, clipping, masks and filters are not rendered, and
+// references to paint servers are replaced by their fallback colors. Shapes
+// whose geometry cannot be parsed, elements with an invalid transform and
+// svg elements with an invalid viewBox are skipped, as browsers do.
+func Rasterize(root *Element, width, height int) (*image.RGBA, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("Invalid image size %dx%d", width, height)
+	}
+
+	documentWidth, documentHeight := documentSize(root, width, height)
+	ctm, err := ViewBoxTransform(
+		Rect{Max: Point{documentWidth, documentHeight}}, "",
+		float64(width), float64(height))
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	viewport := Rect{Max: Point{documentWidth, documentHeight}}
+	rasterize(img, root, ctm, defaultPaint, viewport, true)
+
+	return img, nil
+}
+
+// documentSize returns the size of the outermost svg element, which defaults
+// to the size of its viewBox and then to the size of the image.
+func documentSize(root *Element, width, height int) (float64, float64) {
+	w, h := float64(width), float64(height)
+
+	if viewBox, err := ParseViewBox(root.Attributes["viewBox"]); err == nil {
+		w, h = viewBox.Width(), viewBox.Height()
+	}
+
+	if length, err := parseLength(root.Attributes["width"]); err == nil && length > 0 {
+		w = length
+	}
+	if length, err := parseLength(root.Attributes["height"]); err == nil && length > 0 {
+		h = length
+	}
+
+	return w, h
+}
+
+// paint holds the inherited properties that affect how shapes are painted.
+// Fill and stroke are kept as written, so currentColor is resolved with the
+// color of the element that is painted.
+type paint struct {
+	fill          string
+	fillRule      FillRule
+	fillOpacity   float64
+	stroke        string
+	strokeOpacity float64
+	strokeWidth   float64
+	join          LineJoin
+	cap           LineCap
+	miterLimit    float64
+	dashes        []float64
+	dashOffset    float64
+	color         string
+	visible       bool
+}
+
+// defaultPaint holds the initial values of the properties.
+var defaultPaint = paint{
+	fill:          "black",
+	fillOpacity:   1,
+	stroke:        "none",
+	strokeOpacity: 1,
+	strokeWidth:   1,
+	miterLimit:    4,
+	color:         "black",
+	visible:       true,
+}
+
+// inherit returns the properties of the element, given those of its parent.
+// Values that are missing, invalid or inherit keep the value of the parent.
+func (p paint) inherit(e *Element) paint {
+	value := func(name string) (string, bool) {
+		raw, ok := property(e, name)
+		return raw, ok && raw != "inherit"
+	}
+
+	if raw, ok := value("fill"); ok {
+		p.fill = raw
+	}
+	if raw, ok := value("stroke"); ok {
+		p.stroke = raw
+	}
+	if raw, ok := value("color"); ok {
+		if _, valid := parseColor(raw); valid {
+			p.color = raw
+		}
+	}
+
+	if raw, ok := value("fill-rule"); ok {
+		switch raw {
+		case "nonzero":
+			p.fillRule = NonZero
+		case "evenodd":
+			p.fillRule = EvenOdd
+		}
+	}
+
+	if raw, ok := value("fill-opacity"); ok {
+		if opacity, valid := parseFraction(raw, 1); valid {
+			p.fillOpacity = opacity
+		}
+	}
+	if raw, ok := value("stroke-opacity"); ok {
+		if opacity, valid := parseFraction(raw, 1); valid {
+			p.strokeOpacity = opacity
+		}
+	}
+
+	if raw, ok := value("stroke-width"); ok {
+		if width, err := parseLength(raw); err == nil && width >= 0 {
+			p.strokeWidth = width
+		}
+	}
+
+	if raw, ok := value("stroke-linejoin"); ok {
+		if join, valid := lineJoins[raw]; valid {
+			p.join = join
+		}
+	}
+	if raw, ok := value("stroke-linecap"); ok {
+		if cap, valid := lineCaps[raw]; valid {
+			p.cap = cap
+		}
+	}
+
+	if raw, ok := value("stroke-miterlimit"); ok {
+		if limit, err := parseLength(raw); err == nil && limit >= 1 {
+			p.miterLimit = limit
+		}
+	}
+
+	if raw, ok := value("stroke-dasharray"); ok {
+		p.dashes = parseDashes(raw)
+	}
+	if raw, ok := value("stroke-dashoffset"); ok {
+		if offset, err := parseLength(raw); err == nil {
+			p.dashOffset = offset
+		}
+	}
+
+	if raw, ok := value("visibility"); ok {
+		switch raw {
+		case "visible":
+			p.visible = true
+		case "hidden", "collapse":
+			p.visible = false
+		}
+	}
+
+	return p
+}
+
+// parseDashes parses the value of a stroke-dasharray property. Returns nil
+// for none and for invalid values.
+func parseDashes(raw string) []float64 {
+	fields := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+
+	if len(fields) == 1 && fields[0] == "none" {
+		return nil
+	}
+
+	var dashes []float64
+	for _, field := range fields {
+		length, err := parseLength(field)
+		if err != nil || length < 0 {
+			return nil
+		}
+		dashes = append(dashes, length)
+	}
+
+	return dashes
+}
+
+// rasterize renders the element and its descendants. The viewport is the
+// area that percentages in the geometry of shapes refer to. Elements with an
+// invalid transform, and svg elements with an invalid viewBox, which
+// includes one of zero size, are not rendered, together with their
+// descendants.
+func rasterize(img *image.RGBA, e *Element, ctm Matrix, state paint,
+	viewport Rect, root bool) {
+
+	if referencedOnly[e.Name] {
+		return
+	}
+
+	if display, _ := property(e, "display"); display == "none" {
+		return
+	}
+
+	if raw, ok := e.Attributes["transform"]; ok {
+		matrix, _, err := ParseTransform(raw)
+		if err != nil {
+			return
+		}
+		ctm = ctm.Multiply(matrix)
+	}
+
+	if e.Name == "svg" {
+		matrix, err := viewportTransform(e, root)
+		if err != nil {
+			return
+		}
+		ctm = ctm.Multiply(matrix)
+		viewport = viewportArea(e, viewport)
+	}
+
+	state = state.inherit(e)
+
+	// Elements that are not opaque are rendered on a layer of their own,
+	// which is then blended as a whole.
+	target := img
+	opacity := 1.0
+	if raw, ok := property(e, "opacity"); ok {
+		if value, valid := parseFraction(raw, 1); valid {
+			opacity = value
+		}
+	}
+
+	if opacity <= 0 {
+		return
+	}
+	if opacity < 1 {
+		target = image.NewRGBA(img.Bounds())
+	}
+
+	switch e.Name {
+	case "svg", "g", "a":
+		rasterizeChildren(target, e.Children, ctm, state, viewport)
+
+	case "switch":
+		// Conditional processing attributes are not evaluated, so the first
+		// child is always the one rendered.
+		if len(e.Children) > 0 {
+			rasterizeChildren(target, e.Children[:1], ctm, state, viewport)
+		}
+
+	default:
+		if _, ok := shapeAttributes[e.Name]; ok && state.visible {
+			rasterizeShape(target, resolvePercentages(e, viewport), ctm, state)
+		}
+	}
+
+	if target != img {
+		mask := image.NewUniform(color.Alpha{uint8(math.Round(opacity * 255))})
+		draw.DrawMask(img, img.Bounds(), target, image.Point{}, mask,
+			image.Point{}, draw.Over)
+	}
+}
+
+func rasterizeChildren(img *image.RGBA, children []*Element, ctm Matrix,
+	state paint, viewport Rect) {
+
+	for _, child := range children {
+		rasterize(img, child, ctm, state, viewport, false)
+	}
+}
+
+// rasterizeShape fills and then strokes a path or basic shape. Nothing is
+// painted if the geometry of the shape cannot be parsed.
+func rasterizeShape(img *image.RGBA, e *Element, ctm Matrix, state paint) {
+	path, err := e.ToPath()
+	if err != nil {
+		return
+	}
+
+	scale := math.Sqrt(math.Abs(ctm.Determinant()))
+	if scale == 0 {
+		return
+	}
+
+	// Curves are flattened in user space, within a tenth of a pixel.
+	tolerance := 0.1 / scale
+	polylines := path.polylines(tolerance)
+
+	if fill, ok := parsePaint(state.fill, state.color); ok {
+		var polygons [][]Point
+		for _, polyline := range polylines {
+			polygons = append(polygons, polyline.points)
+		}
+
+		fill.a *= state.fillOpacity
+		fillPolygons(img, transformPolygons(polygons, ctm), state.fillRule, fill)
+	}
+
+	if stroke, ok := parsePaint(state.stroke, state.color); ok {
+		if state.dashes != nil {
+			polylines = dashPolylines(polylines, state.dashes, state.dashOffset)
+		}
+
+		polygons := strokePolylines(polylines, state.strokeWidth, state.join,
+			state.cap, state.miterLimit, tolerance)
+
+		stroke.a *= state.strokeOpacity
+		fillPolygons(img, transformPolygons(polygons, ctm), NonZero, stroke)
+	}
+}
+
+// viewportArea returns the area that percentages refer to inside the svg
+// element: its viewBox, or else its own size, which defaults to the size of
+// the viewport it is placed in.
+func viewportArea(e *Element, viewport Rect) Rect {
+	if viewBox, err := ParseViewBox(e.Attributes["viewBox"]); err == nil {
+		return viewBox
+	}
+
+	width, height := viewport.Width(), viewport.Height()
+	if length, ok := resolveLength(e.Attributes["width"], width); ok && length > 0 {
+		width = length
+	}
+	if length, ok := resolveLength(e.Attributes["height"], height); ok && length > 0 {
+		height = length
+	}
+
+	return Rect{Max: Point{width, height}}
+}
+
+// resolvePercentages returns a copy of the shape element with the lengths of
+// its geometry, including percentages, in user units. Horizontal lengths refer to the width of
+// the viewport, vertical lengths to its height and radii of circles to its
+// normalized diagonal.
+func resolvePercentages(e *Element, viewport Rect) *Element {
+	resolved := *e
+	resolved.Attributes = map[string]string{}
+	for name, value := range e.Attributes {
+		resolved.Attributes[name] = value
+	}
+
+	width, height := viewport.Width(), viewport.Height()
+	for _, name := range shapeAttributes[e.Name] {
+		reference := math.Hypot(width, height) / math.Sqrt2
+		switch name {
+		case "x", "cx", "x1", "x2", "width", "rx":
+			reference = width
+		case "y", "cy", "y1", "y2", "height", "ry":
+			reference = height
+		}
+
+		if length, ok := resolveLength(e.Attributes[name], reference); ok {
+			resolved.Attributes[name] = formatNumber(length, -1, false)
+		}
+	}
+
+	return &resolved
+}
+
+// resolveLength parses a length, which may be a percentage of the reference
+// length. Returns false if the value is not valid.
+func resolveLength(raw string, reference float64) (float64, bool) {
+	raw = strings.TrimSpace(raw)
+	if !strings.HasSuffix(raw, "%") {
+		length, err := parseLength(raw)
+		return length, err == nil
+	}
+
+	percentage, err := strconv.ParseFloat(raw[:len(raw)-1], 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return percentage / 100 * reference, true
+}
+
+// transformPolygons returns the polygons with their points transformed by
+// the matrix.
+func transformPolygons(polygons [][]Point, m Matrix) [][]Point {
+	result := make([][]Point, len(polygons))
+
+	for i, polygon := range polygons {
+		result[i] = make([]Point, len(polygon))
+		for j, point := range polygon {
+			result[i][j] = m.Apply(point)
+		}
+	}
+
+	return result
+}
+
+// edge is a non-horizontal edge of a polygon, with its end points ordered
+// from top to bottom. Winding is 1 for edges that run down and -1 for edges
+// that run up.
+type edge struct {
+	top, bottom Point
+	winding     int
+}
+
+// x returns the horizontal position of the edge at the height.
+func (e edge) x(y float64) float64 {
+	t := (y - e.top.Y) / (e.bottom.Y - e.top.Y)
+	return e.top.X + t*(e.bottom.X-e.top.X)
+}
+
+// crossing is where an edge crosses a sub-scanline.
+type crossing struct {
+	x       float64
+	winding int
+}
+
+// fillPolygons paints the inside of the polygons, which are implicitly
+// closed, with the color. Pixels are blended according to the fraction of
+// them that is covered, measured on sub-scanlines.
+func fillPolygons(img *image.RGBA, polygons [][]Point, rule FillRule, c rgba) {
+	if c.a <= 0 {
+		return
+	}
+
+	var edges []edge
+	for _, polygon := range polygons {
+		for i, from := range polygon {
+			to := polygon[(i+1)%len(polygon)]
+			if !finite(from) || !finite(to) || from.Y == to.Y {
+				continue
+			}
+
+			if from.Y < to.Y {
+				edges = append(edges, edge{from, to, 1})
+			} else {
+				edges = append(edges, edge{to, from, -1})
+			}
+		}
+	}
+
+	if len(edges) == 0 {
+		return
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		return edges[i].top.Y < edges[j].top.Y
+	})
+
+	bounds := img.Bounds()
+	width := bounds.Dx()
+
+	bottom := edges[0].bottom.Y
+	for _, e := range edges {
+		bottom = math.Max(bottom, e.bottom.Y)
+	}
+
+	// Rows are clamped to the image before they are converted to integers,
+	// as the polygons may lie arbitrarily far away from it.
+	clamp := func(y float64) int {
+		return int(math.Max(float64(bounds.Min.Y),
+			math.Min(y, float64(bounds.Max.Y))))
+	}
+
+	top, end := clamp(math.Floor(edges[0].top.Y)), clamp(math.Ceil(bottom))
+	if top >= end {
+		return
+	}
+
+	// Coverage of each pixel in the row: partial holds the coverage of the
+	// pixels at the ends of spans and full the changes in the coverage of
+	// the pixels that spans cover completely.
+	partial := make([]float64, width+1)
+	full := make([]float64, width+1)
+
+	var active []edge
+	var crossings []crossing
+	next := 0
+
+	for y := top; y < end; y++ {
+		row := float64(y)
+
+		for next < len(edges) && edges[next].top.Y < row+1 {
+			active = append(active, edges[next])
+			next++
+		}
+
+		remaining := active[:0]
+		for _, e := range active {
+			if e.bottom.Y > row {
+				remaining = append(remaining, e)
+			}
+		}
+		active = remaining
+
+		for i := range partial {
+			partial[i], full[i] = 0, 0
+		}
+
+		for s := 0; s < subsamples; s++ {
+			sy := row + (float64(s)+0.5)/subsamples
+
+			crossings = crossings[:0]
+			for _, e := range active {
+				if e.top.Y <= sy && sy < e.bottom.Y {
+					crossings = append(crossings, crossing{e.x(sy), e.winding})
+				}
+			}
+
+			sort.Slice(crossings, func(i, j int) bool {
+				return crossings[i].x < crossings[j].x
+			})
+
+			winding := 0
+			for i, crossing := range crossings {
+				winding += crossing.winding
+
+				inside := winding != 0
+				if rule == EvenOdd {
+					inside = winding%2 != 0
+				}
+
+				if inside && i+1 < len(crossings) {
+					addSpan(partial, full,
+						crossing.x-float64(bounds.Min.X),
+						crossings[i+1].x-float64(bounds.Min.X))
+				}
+			}
+		}
+
+		blendRow(img, y, partial, full, c)
+	}
+}
+
+// addSpan adds the coverage of a span of a sub-scanline between x0 and x1,
+// measured from the left of the row.
+func addSpan(partial, full []float64, x0, x1 float64) {
+	width := float64(len(partial) - 1)
+	x0, x1 = math.Max(x0, 0), math.Min(x1, width)
+	if x0 >= x1 {
+		return
+	}
+
+	const weight = 1.0 / subsamples
+
+	i0, i1 := int(x0), int(x1)
+	if i0 == i1 {
+		partial[i0] += (x1 - x0) * weight
+		return
+	}
+
+	partial[i0] += (float64(i0+1) - x0) * weight
+	full[i0+1] += weight
+	full[i1] -= weight
+	partial[i1] += (x1 - float64(i1)) * weight
+}
+
+// blendRow blends the color over the pixels of the row, in proportion to
+// their coverage.
+func blendRow(img *image.RGBA, y int, partial, full []float64, c rgba) {
+	bounds := img.Bounds()
+	offset := img.PixOffset(bounds.Min.X, y)
+
+	var coverage float64
+	for x := 0; x < bounds.Dx(); x++ {
+		coverage += full[x]
+
+		alpha := math.Min(1, coverage+partial[x]) * c.a
+		if alpha <= 0 {
+			continue
+		}
+
+		pixel := img.Pix[offset+4*x : offset+4*x+4]
+		for i, component := range [4]float64{c.r, c.g, c.b, 1} {
+			blended := component*alpha*255 + float64(pixel[i])*(1-alpha)
+			pixel[i] = uint8(math.Round(math.Min(255, blended)))
+		}
+	}
+}
+
+// finite returns true if both coordinates of the point are finite.
+func finite(p Point) bool {
+	return !math.IsInf(p.X, 0) && !math.IsNaN(p.X) &&
+		!math.IsInf(p.Y, 0) && !math.IsNaN(p.Y)
+}
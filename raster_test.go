@@ -0,0 +1,295 @@
+package svg_test
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+type pixel struct {
+	x, y     int
+	expected color.RGBA
+}
+
+var (
+	transparent = color.RGBA{}
+	black       = color.RGBA{0, 0, 0, 255}
+	white       = color.RGBA{255, 255, 255, 255}
+	red         = color.RGBA{255, 0, 0, 255}
+	blue        = color.RGBA{0, 0, 255, 255}
+)
+
+func TestRasterize(t *testing.T) {
+	tests := []struct {
+		description string
+		raw         string
+		pixels      []pixel
+	}{
+		{
+			description: "default fill",
+			raw:         `<svg width="10" height="10"><rect x="2" y="2" width="6" height="6"/></svg>`,
+			pixels:      []pixel{{1, 1, transparent}, {5, 5, black}, {8, 8, transparent}},
+		},
+		{
+			description: "fill colors",
+			raw: `<svg width="10" height="10">
+				<rect width="5" height="10" fill="#f00"/>
+				<rect x="5" width="5" height="10" style="fill:rgb(0, 0, 255)"/>
+			</svg>`,
+			pixels: []pixel{{2, 5, red}, {7, 5, blue}},
+		},
+		{
+			description: "no fill",
+			raw:         `<svg width="10" height="10"><circle cx="5" cy="5" r="4" fill="none"/></svg>`,
+			pixels:      []pixel{{5, 5, transparent}},
+		},
+		{
+			description: "anti-aliased edge",
+			raw:         `<svg width="10" height="10"><rect width="5.5" height="10" fill="red"/></svg>`,
+			pixels:      []pixel{{4, 5, red}, {5, 5, color.RGBA{128, 0, 0, 128}}},
+		},
+		{
+			description: "stroke",
+			raw: `<svg width="10" height="10">
+				<line x1="0" y1="5" x2="10" y2="5" stroke="red" stroke-width="2"/>
+			</svg>`,
+			pixels: []pixel{{5, 4, red}, {5, 5, red}, {5, 3, transparent}, {5, 6, transparent}},
+		},
+		{
+			description: "stroke over fill",
+			raw: `<svg width="10" height="10">
+				<rect x="2" y="2" width="6" height="6" fill="blue" stroke="red" stroke-width="2"/>
+			</svg>`,
+			pixels: []pixel{{1, 5, red}, {2, 5, red}, {3, 5, blue}, {0, 5, transparent}},
+		},
+		{
+			description: "inherited properties",
+			raw: `<svg width="10" height="10">
+				<g fill="red" stroke="blue" stroke-width="0"><rect width="10" height="10"/></g>
+			</svg>`,
+			pixels: []pixel{{5, 5, red}},
+		},
+		{
+			description: "current color",
+			raw: `<svg width="10" height="10" color="blue">
+				<rect width="10" height="10" fill="currentColor"/>
+			</svg>`,
+			pixels: []pixel{{5, 5, blue}},
+		},
+		{
+			description: "current color in lower case",
+			raw: `<svg width="10" height="10" color="blue">
+				<rect width="10" height="10" fill="currentcolor"/>
+			</svg>`,
+			pixels: []pixel{{5, 5, blue}},
+		},
+		{
+			description: "even-odd fill rule",
+			raw: `<svg width="10" height="10">
+				<path d="M 0 0 H 10 V 10 H 0 Z M 3 3 H 7 V 7 H 3 Z" fill-rule="evenodd"/>
+			</svg>`,
+			pixels: []pixel{{1, 1, black}, {5, 5, transparent}},
+		},
+		{
+			description: "nonzero fill rule",
+			raw: `<svg width="10" height="10">
+				<path d="M 0 0 H 10 V 10 H 0 Z M 3 3 H 7 V 7 H 3 Z"/>
+			</svg>`,
+			pixels: []pixel{{1, 1, black}, {5, 5, black}},
+		},
+		{
+			description: "fill opacity",
+			raw: `<svg width="10" height="10">
+				<rect width="10" height="10" fill="red" fill-opacity="0.5"/>
+			</svg>`,
+			pixels: []pixel{{5, 5, color.RGBA{128, 0, 0, 128}}},
+		},
+		{
+			description: "group opacity",
+			raw: `<svg width="10" height="10">
+				<g opacity="0.5">
+					<rect width="10" height="10" fill="blue"/>
+					<rect width="10" height="10" fill="red"/>
+				</g>
+			</svg>`,
+			pixels: []pixel{{5, 5, color.RGBA{128, 0, 0, 128}}},
+		},
+		{
+			description: "transform",
+			raw: `<svg width="10" height="10">
+				<rect width="2" height="2" fill="red" transform="translate(4 4) scale(2)"/>
+			</svg>`,
+			pixels: []pixel{{3, 3, transparent}, {5, 5, red}, {7, 7, red}, {8, 8, transparent}},
+		},
+		{
+			description: "viewBox",
+			raw: `<svg width="10" height="10" viewBox="0 0 100 100">
+				<rect x="50" y="50" width="50" height="50" fill="red"/>
+			</svg>`,
+			pixels: []pixel{{2, 2, transparent}, {7, 7, red}},
+		},
+		{
+			description: "scaled to the image",
+			raw:         `<svg width="5" height="5"><rect x="2.5" width="2.5" height="5" fill="red"/></svg>`,
+			pixels:      []pixel{{2, 5, transparent}, {7, 5, red}},
+		},
+		{
+			description: "hidden elements",
+			raw: `<svg width="10" height="10">
+				<rect width="10" height="10" fill="red" display="none"/>
+				<g visibility="hidden"><rect width="10" height="10" fill="red"/></g>
+				<defs><rect width="10" height="10" fill="red"/></defs>
+			</svg>`,
+			pixels: []pixel{{5, 5, transparent}},
+		},
+		{
+			description: "dashes",
+			raw: `<svg width="10" height="10">
+				<path d="M 0 5 H 10" stroke="red" stroke-width="2" stroke-dasharray="2"/>
+			</svg>`,
+			pixels: []pixel{{1, 5, red}, {3, 5, transparent}, {5, 5, red}},
+		},
+		{
+			description: "square cap",
+			raw: `<svg width="10" height="10">
+				<path d="M 3 5 H 7" stroke="red" stroke-width="2" stroke-linecap="square"/>
+			</svg>`,
+			pixels: []pixel{{1, 5, transparent}, {2, 5, red}, {7, 5, red}, {8, 5, transparent}},
+		},
+		{
+			description: "miter join",
+			raw: `<svg width="10" height="10">
+				<path d="M 2 8 V 2 H 8" stroke="red" stroke-width="2" fill="none"/>
+			</svg>`,
+			pixels: []pixel{{1, 1, red}, {5, 5, transparent}},
+		},
+		{
+			description: "percentages",
+			raw: `<svg width="10" height="10">
+				<rect width="100%" height="100%" fill="white"/>
+				<rect x="50%" width="50%" height="100%" fill="red"/>
+			</svg>`,
+			pixels: []pixel{{2, 5, white}, {7, 5, red}},
+		},
+		{
+			description: "percentages of viewBox",
+			raw: `<svg width="10" height="10" viewBox="0 0 20 20">
+				<circle cx="50%" cy="50%" r="20%" fill="red"/>
+			</svg>`,
+			pixels: []pixel{{5, 5, red}, {5, 2, transparent}, {5, 8, transparent}},
+		},
+		{
+			description: "invalid transform is skipped",
+			raw: `<svg width="10" height="10">
+				<rect width="10" height="10" fill="blue"/>
+				<g transform="scale("><rect width="10" height="10" fill="red"/></g>
+				<rect width="5" height="10" fill="red" transform="rotate(x)"/>
+			</svg>`,
+			pixels: []pixel{{2, 5, blue}, {7, 5, blue}},
+		},
+		{
+			description: "empty viewBox",
+			raw: `<svg width="10" height="10" viewBox="0 0 0 0">
+				<rect width="10" height="10" fill="red"/>
+			</svg>`,
+			pixels: []pixel{{5, 5, transparent}},
+		},
+		{
+			description: "empty viewBox of nested svg",
+			raw: `<svg width="10" height="10">
+				<rect width="10" height="10" fill="blue"/>
+				<svg viewBox="0 0 0 10"><rect width="10" height="10" fill="red"/></svg>
+			</svg>`,
+			pixels: []pixel{{5, 5, blue}},
+		},
+		{
+			description: "shapes far outside the image",
+			raw: `<svg width="10" height="10">
+				<rect y="1e300" width="10" height="1e301" fill="blue"/>
+				<rect y="-1e301" width="10" height="1e300" fill="blue"/>
+				<rect x="-1e300" y="-1e300" width="1e301" height="1e301" fill="red"/>
+			</svg>`,
+			pixels: []pixel{{0, 0, red}, {9, 9, red}},
+		},
+		{
+			description: "invalid geometry is skipped",
+			raw: `<svg width="10" height="10">
+				<rect width="10" height="10" fill="blue"/>
+				<path d="L 10 10" fill="red"/>
+				<rect width="5" height="invalid" fill="red"/>
+			</svg>`,
+			pixels: []pixel{{2, 5, blue}, {7, 5, blue}},
+		},
+		{
+			description: "bevel join",
+			raw: `<svg width="10" height="10">
+				<path d="M 2 8 V 2 H 8" stroke="red" stroke-width="2" fill="none"
+					stroke-linejoin="bevel"/>
+			</svg>`,
+			pixels: []pixel{{1, 1, color.RGBA{128, 0, 0, 128}}, {1, 3, red}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			root, err := New(strings.NewReader(test.raw))
+			if err != nil {
+				t.Fatalf("Rasterize: unexpected error: %v", err)
+			}
+
+			img, err := Rasterize(root, 10, 10)
+			if err != nil {
+				t.Fatalf("Rasterize: unexpected error: %v", err)
+			}
+
+			for _, p := range test.pixels {
+				actual := img.RGBAAt(p.x, p.y)
+				if !colorsClose(actual, p.expected) {
+					t.Errorf("Rasterize: expected %v at (%d, %d), actual %v",
+						p.expected, p.x, p.y, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestRasterizeErrors(t *testing.T) {
+	tests := []struct {
+		description   string
+		raw           string
+		width, height int
+		expected      string
+	}{
+		{
+			description: "invalid size",
+			raw:         `<svg/>`,
+			width:       0, height: 10,
+			expected: "Invalid image size 0x10",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			root, err := New(strings.NewReader(test.raw))
+			if err != nil {
+				t.Fatalf("Rasterize: unexpected error: %v", err)
+			}
+
+			_, err = Rasterize(root, test.width, test.height)
+			if err == nil || !strings.HasPrefix(err.Error(), test.expected) {
+				t.Errorf("Rasterize: expected %q, actual %v", test.expected, err)
+			}
+		})
+	}
+}
+
+// colorsClose returns true if the components of the colors differ by at most
+// a small amount, to allow for rounding and approximated curves.
+func colorsClose(a, b color.RGBA) bool {
+	close := func(x, y uint8) bool {
+		return int(x)-int(y) <= 3 && int(y)-int(x) <= 3
+	}
+	return close(a.R, b.R) && close(a.G, b.G) && close(a.B, b.B) && close(a.A, b.A)
+}
@@ -0,0 +1,245 @@
+package svg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// RenderOptions configures how RenderWithOptions writes a document. The zero
+// value writes the nodes exactly as they are held, the same as Render.
+type RenderOptions struct {
+	// Indent is written once per level of nesting in front of every node on
+	// a line of its own. Existing whitespace between the nodes is replaced.
+	// Content where whitespace is significant, such as text elements and
+	// elements with text, is written as it is. An empty Indent writes no
+	// line breaks.
+	Indent string
+
+	// SelfClose writes elements without content as self-closing tags.
+	SelfClose bool
+
+	// Declaration writes an XML declaration in front of the document, unless
+	// the prolog already contains one.
+	Declaration bool
+
+	// SingleQuote quotes attribute values with single instead of double
+	// quotes.
+	SingleQuote bool
+
+	// Minify leaves out whitespace that is not significant and writes
+	// elements without content as self-closing tags. Indent is ignored.
+	Minify bool
+}
+
+// xmlDeclaration is written when RenderOptions asks for a declaration.
+var xmlDeclaration = ProcInst{Target: "xml", Inst: `version="1.0" encoding="UTF-8"`}
+
+// textElements are elements whose whitespace is rendered.
+var textElements = map[string]bool{"text": true, "tspan": true, "textPath": true}
+
+// RenderWithOptions creates an SVG output from the element, together with
+// its prolog and epilog, formatted according to opts. Returns an error if the
+// element is empty.
+func (e *Element) RenderWithOptions(w io.Writer, opts RenderOptions) error {
+	if opts.Minify {
+		opts.Indent = ""
+		opts.SelfClose = true
+	}
+
+	var nodes []Node
+	if opts.Declaration && !hasDeclaration(e.Prolog) {
+		nodes = append(nodes, xmlDeclaration)
+	}
+	nodes = append(nodes, e.Prolog...)
+	nodes = append(nodes, e)
+	nodes = append(nodes, e.Epilog...)
+
+	if opts.Indent != "" || opts.Minify {
+		nodes = withoutWhitespace(nodes)
+	}
+
+	r := &renderer{w: bufio.NewWriter(w), opts: opts}
+	declarations := missingDeclarations(e)
+
+	for i, node := range nodes {
+		if i > 0 && opts.Indent != "" {
+			r.w.WriteByte('\n')
+		}
+
+		if err := r.node(node, declarations, 0, false); err != nil {
+			return fmt.Errorf("Could not render element: %s", err)
+		}
+	}
+
+	return r.w.Flush()
+}
+
+// renderer writes nodes as XML.
+type renderer struct {
+	w    *bufio.Writer
+	opts RenderOptions
+
+	// written is set once anything has been written.
+	written bool
+}
+
+// node writes the node at the given depth of nesting. Whitespace is kept
+// as it is inside inline content. The namespace declarations are added to the
+// attributes of an element.
+func (r *renderer) node(node Node, declarations map[string]string, depth int,
+	inline bool) error {
+
+	defer func() { r.written = true }()
+
+	switch node := node.(type) {
+	case *Element:
+		return r.element(node, declarations, depth, inline)
+
+	case Text:
+		r.text(node)
+
+	case Comment:
+		if strings.Contains(node.Data, "--") {
+			return fmt.Errorf("comment %q contains --", node.Data)
+		}
+		r.w.WriteString("<!--" + node.Data + "-->")
+
+	case ProcInst:
+		if node.Target == "" || strings.Contains(node.Inst, "?>") {
+			return fmt.Errorf("invalid processing instruction %q", node.Target)
+		}
+		if strings.EqualFold(node.Target, "xml") && r.written {
+			return fmt.Errorf("XML declaration is not at the start of the document")
+		}
+
+		r.w.WriteString("<?" + node.Target)
+		if node.Inst != "" {
+			r.w.WriteString(" " + node.Inst)
+		}
+		r.w.WriteString("?>")
+
+	case Directive:
+		r.w.WriteString("<!" + node.Data + ">")
+	}
+
+	return nil
+}
+
+func (r *renderer) element(e *Element, declarations map[string]string,
+	depth int, inline bool) error {
+
+	if e.Name == "" {
+		return fmt.Errorf("element without a name")
+	}
+
+	start := serialize(e, declarations)
+
+	r.w.WriteString("<" + e.Name)
+	for _, attr := range start.Attr {
+		r.attribute(attr.Name.Local, attr.Value)
+	}
+
+	nodes := content(e)
+
+	switch e.Attributes["xml:space"] {
+	case "preserve":
+		inline = true
+	case "default":
+		inline = false
+	}
+	inline = inline || textElements[e.Name] || textContent(nodes) != ""
+
+	indent := r.opts.Indent != "" && !inline
+	if indent || (r.opts.Minify && !inline) {
+		nodes = withoutWhitespace(nodes)
+	}
+
+	if len(nodes) == 0 && r.opts.SelfClose {
+		r.w.WriteString("/>")
+		return nil
+	}
+	r.w.WriteByte('>')
+
+	for _, node := range nodes {
+		if indent {
+			r.newline(depth + 1)
+		}
+		if err := r.node(node, nil, depth+1, inline); err != nil {
+			return err
+		}
+	}
+
+	if indent && len(nodes) > 0 {
+		r.newline(depth)
+	}
+	r.w.WriteString("</" + e.Name + ">")
+
+	return nil
+}
+
+// attribute writes an attribute with its value escaped and quoted.
+func (r *renderer) attribute(name, value string) {
+	quote := `"`
+	if r.opts.SingleQuote {
+		quote = "'"
+	}
+
+	r.w.WriteString(" " + name + "=" + quote)
+	r.w.WriteString(attributeEscaper.Replace(value))
+	r.w.WriteString(quote)
+}
+
+// text writes the text escaped, or as CDATA sections if it is marked as such.
+// A CDATA section cannot contain its own end delimiter, so the text is split
+// into several sections around it.
+func (r *renderer) text(text Text) {
+	if !text.CDATA {
+		r.w.WriteString(textEscaper.Replace(text.Data))
+		return
+	}
+
+	data := strings.Replace(text.Data, "]]>", "]]]]><![CDATA[>", -1)
+	r.w.WriteString("<![CDATA[" + data + "]]>")
+}
+
+// newline starts a new line indented to the given depth.
+func (r *renderer) newline(depth int) {
+	r.w.WriteByte('\n')
+	r.w.WriteString(strings.Repeat(r.opts.Indent, depth))
+}
+
+// textEscaper escapes the characters that cannot appear in text as they are.
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// attributeEscaper escapes the characters that cannot appear in attribute
+// values as they are, including whitespace that would be normalized when the
+// value is read back.
+var attributeEscaper = strings.NewReplacer(
+	"&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&#34;", "'", "&#39;",
+	"\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+
+// withoutWhitespace returns the nodes without text that is only whitespace.
+func withoutWhitespace(nodes []Node) []Node {
+	var result []Node
+
+	for _, node := range nodes {
+		if text, ok := node.(Text); ok && strings.TrimSpace(text.Data) == "" {
+			continue
+		}
+		result = append(result, node)
+	}
+
+	return result
+}
+
+// hasDeclaration returns true if the nodes contain an XML declaration.
+func hasDeclaration(nodes []Node) bool {
+	for _, node := range nodes {
+		if inst, ok := node.(ProcInst); ok && inst.Target == "xml" {
+			return true
+		}
+	}
+	return false
+}
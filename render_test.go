@@ -0,0 +1,172 @@
+package svg_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+func TestElementRenderWithOptions(t *testing.T) {
+	const raw = "<!-- icon -->\n" +
+		"<svg width=\"10\">\n" +
+		"  <g fill=\"red\"><rect width=\"1\"></rect>\n" +
+		"    <!-- dot -->\n" +
+		"    <circle r=\"1\"/></g>\n" +
+		"  <text> Hello <tspan>big</tspan> world </text>\n" +
+		"  <g xml:space=\"preserve\"> <path/> </g>\n" +
+		"</svg>\n"
+
+	tests := []struct {
+		description string
+		opts        RenderOptions
+		expected    string
+	}{
+		{
+			description: "zero options",
+			opts:        RenderOptions{},
+			expected: "<!-- icon -->\n" +
+				"<svg width=\"10\">\n" +
+				"  <g fill=\"red\"><rect width=\"1\"></rect>\n" +
+				"    <!-- dot -->\n" +
+				"    <circle r=\"1\"></circle></g>\n" +
+				"  <text> Hello <tspan>big</tspan> world </text>\n" +
+				"  <g xml:space=\"preserve\"> <path></path> </g>\n" +
+				"</svg>\n",
+		},
+		{
+			description: "indent",
+			opts:        RenderOptions{Indent: "\t", SelfClose: true},
+			expected: "<!-- icon -->\n" +
+				"<svg width=\"10\">\n" +
+				"\t<g fill=\"red\">\n" +
+				"\t\t<rect width=\"1\"/>\n" +
+				"\t\t<!-- dot -->\n" +
+				"\t\t<circle r=\"1\"/>\n" +
+				"\t</g>\n" +
+				"\t<text> Hello <tspan>big</tspan> world </text>\n" +
+				"\t<g xml:space=\"preserve\"> <path/> </g>\n" +
+				"</svg>",
+		},
+		{
+			description: "minify",
+			opts:        RenderOptions{Minify: true, Indent: "  "},
+			expected: "<!-- icon --><svg width=\"10\"><g fill=\"red\">" +
+				"<rect width=\"1\"/><!-- dot --><circle r=\"1\"/></g>" +
+				"<text> Hello <tspan>big</tspan> world </text>" +
+				"<g xml:space=\"preserve\"> <path/> </g></svg>",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			element, err := New(strings.NewReader(raw))
+			if err != nil {
+				t.Fatalf("New: unexpected error: %s", err)
+			}
+
+			buf := &bytes.Buffer{}
+			if err := element.RenderWithOptions(buf, test.opts); err != nil {
+				t.Fatalf("RenderWithOptions: unexpected error: %s", err)
+			}
+
+			if actual := buf.String(); actual != test.expected {
+				t.Fatalf("RenderWithOptions: expected %s, actual %s",
+					test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestElementRenderWithOptionsAttributes(t *testing.T) {
+	tests := []struct {
+		description string
+		element     *Element
+		opts        RenderOptions
+		expected    string
+	}{
+		{
+			description: "declaration",
+			element:     &Element{Name: "svg"},
+			opts:        RenderOptions{Declaration: true, Indent: " "},
+			expected: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<svg></svg>`,
+		},
+		{
+			description: "existing declaration",
+			element: &Element{
+				Name:   "svg",
+				Prolog: []Node{ProcInst{Target: "xml", Inst: `version="1.0"`}},
+			},
+			opts:     RenderOptions{Declaration: true},
+			expected: `<?xml version="1.0"?><svg></svg>`,
+		},
+		{
+			description: "single quotes",
+			element: &Element{
+				Name:       "text",
+				Attributes: map[string]string{"font-family": `'A' "B"`},
+			},
+			opts:     RenderOptions{SingleQuote: true, SelfClose: true},
+			expected: `<text font-family='&#39;A&#39; &#34;B&#34;'/>`,
+		},
+		{
+			description: "escaped whitespace",
+			element: &Element{
+				Name:       "path",
+				Attributes: map[string]string{"d": "M 0 0\n\tL 1 1"},
+			},
+			expected: `<path d="M 0 0&#xA;&#x9;L 1 1"></path>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := test.element.RenderWithOptions(buf, test.opts); err != nil {
+				t.Fatalf("RenderWithOptions: unexpected error: %s", err)
+			}
+
+			if actual := buf.String(); actual != test.expected {
+				t.Fatalf("RenderWithOptions: expected %s, actual %s",
+					test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestElementRenderWithOptionsErrors(t *testing.T) {
+	tests := []struct {
+		description string
+		element     *Element
+	}{
+		{
+			description: "invalid comment",
+			element: &Element{
+				Name:  "svg",
+				Nodes: []Node{Comment{Data: "a -- b"}},
+			},
+		},
+		{
+			description: "misplaced declaration",
+			element: &Element{
+				Name:   "svg",
+				Prolog: []Node{Comment{}, ProcInst{Target: "xml"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := test.element.RenderWithOptions(&bytes.Buffer{}, RenderOptions{})
+			if err == nil {
+				t.Fatalf("RenderWithOptions: expected error, actual nil")
+			}
+
+			if !strings.HasPrefix(err.Error(), "Could not render element") {
+				t.Fatalf("RenderWithOptions: unexpected error %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package svg
+
+import "math"
+
+// segment is a drawn part of a path, parameterized over the interval [0, 1].
+type segment interface {
+	// point returns the point of the segment at t.
+	point(t float64) Point
+
+	// derivative returns the derivative of the segment at t.
+	derivative(t float64) Point
+
+	// extrema returns the values of t in (0, 1) at which the segment has a
+	// horizontal or vertical tangent.
+	extrema() []float64
+}
+
+// line is a straight segment.
+type line struct {
+	from, to Point
+}
+
+func (l line) point(t float64) Point {
+	return l.from.lerp(l.to, t)
+}
+
+func (l line) derivative(t float64) Point {
+	return l.to.sub(l.from)
+}
+
+func (l line) extrema() []float64 {
+	return nil
+}
+
+// quadratic is a quadratic Bézier curve.
+type quadratic struct {
+	p0, p1, p2 Point
+}
+
+func (q quadratic) point(t float64) Point {
+	mt := 1 - t
+	return q.p0.mul(mt * mt).add(q.p1.mul(2 * mt * t)).add(q.p2.mul(t * t))
+}
+
+func (q quadratic) derivative(t float64) Point {
+	return q.p1.sub(q.p0).mul(2 * (1 - t)).add(q.p2.sub(q.p1).mul(2 * t))
+}
+
+func (q quadratic) extrema() []float64 {
+	var ts []float64
+
+	for _, axis := range [][3]float64{
+		{q.p0.X, q.p1.X, q.p2.X},
+		{q.p0.Y, q.p1.Y, q.p2.Y},
+	} {
+		ts = append(ts, solveLinear(axis[0]-2*axis[1]+axis[2], axis[1]-axis[0])...)
+	}
+
+	return inUnitInterval(ts)
+}
+
+// cubic is a cubic Bézier curve.
+type cubic struct {
+	p0, p1, p2, p3 Point
+}
+
+func (c cubic) point(t float64) Point {
+	mt := 1 - t
+	return c.p0.mul(mt * mt * mt).add(c.p1.mul(3 * mt * mt * t)).
+		add(c.p2.mul(3 * mt * t * t)).add(c.p3.mul(t * t * t))
+}
+
+func (c cubic) derivative(t float64) Point {
+	mt := 1 - t
+	return c.p1.sub(c.p0).mul(3 * mt * mt).add(c.p2.sub(c.p1).mul(6 * mt * t)).
+		add(c.p3.sub(c.p2).mul(3 * t * t))
+}
+
+func (c cubic) extrema() []float64 {
+	var ts []float64
+
+	for _, axis := range [][4]float64{
+		{c.p0.X, c.p1.X, c.p2.X, c.p3.X},
+		{c.p0.Y, c.p1.Y, c.p2.Y, c.p3.Y},
+	} {
+		a := -axis[0] + 3*axis[1] - 3*axis[2] + axis[3]
+		b := 2 * (axis[0] - 2*axis[1] + axis[2])
+		c := axis[1] - axis[0]
+		ts = append(ts, solveQuadratic(a, b, c)...)
+	}
+
+	return inUnitInterval(ts)
+}
+
+// arcSegment is an elliptical arc.
+type arcSegment struct {
+	Arc
+}
+
+func (a arcSegment) point(t float64) Point {
+	return a.Point(a.Start + a.Sweep*t)
+}
+
+func (a arcSegment) derivative(t float64) Point {
+	return a.Arc.derivative(a.Start + a.Sweep*t).mul(a.Sweep)
+}
+
+func (a arcSegment) extrema() []float64 {
+	sin, cos := math.Sincos(a.Rotation)
+
+	// The tangent of the ellipse is vertical at thetaX and horizontal at
+	// thetaY, as well as at the opposite points.
+	thetaX := math.Atan2(-a.RY*sin, a.RX*cos)
+	thetaY := math.Atan2(a.RY*cos, a.RX*sin)
+
+	var ts []float64
+	for _, theta := range []float64{thetaX, thetaY} {
+		for k := -4; k <= 4; k++ {
+			ts = append(ts, (theta+float64(k)*math.Pi-a.Start)/a.Sweep)
+		}
+	}
+
+	return inUnitInterval(ts)
+}
+
+// subpath is a sequence of connected segments of a path.
+type subpath struct {
+	start    Point
+	segments []segment
+	closed   bool
+}
+
+// subpaths splits the path into the subpaths it draws, following the same
+// rules as Subpaths. Arcs that are drawn as straight lines become lines and
+// arcs that are omitted are left out.
+func (p *Path) subpaths() []*subpath {
+	var result []*subpath
+	var path *subpath
+	var start, current Point
+
+	for _, command := range p.Normalize().Commands {
+		params := command.Params
+
+		if command.Symbol == "M" {
+			start = Point{params[0], params[1]}
+			current = start
+			path = &subpath{start: start}
+			result = append(result, path)
+			continue
+		}
+
+		if path == nil {
+			path = &subpath{start: start}
+			result = append(result, path)
+		}
+
+		switch command.Symbol {
+		case "Z":
+			if current != start {
+				path.segments = append(path.segments, line{current, start})
+			}
+			path.closed = true
+			path = nil
+			current = start
+			continue
+
+		case "L":
+			path.segments = append(path.segments,
+				line{current, Point{params[0], params[1]}})
+
+		case "Q":
+			path.segments = append(path.segments, quadratic{current,
+				Point{params[0], params[1]}, Point{params[2], params[3]}})
+
+		case "C":
+			path.segments = append(path.segments, cubic{current,
+				Point{params[0], params[1]}, Point{params[2], params[3]},
+				Point{params[4], params[5]}})
+
+		case "A":
+			to := Point{params[5], params[6]}
+			arc, ok := ArcCenter(current, to, params[0], params[1],
+				params[2], params[3] != 0, params[4] != 0)
+
+			if ok {
+				path.segments = append(path.segments, arcSegment{arc})
+			} else if current != to {
+				path.segments = append(path.segments, line{current, to})
+			}
+		}
+
+		current = Point{params[len(params)-2], params[len(params)-1]}
+	}
+
+	return result
+}
+
+// solveLinear returns the root of a*t + b = 0, if there is a single one.
+func solveLinear(a, b float64) []float64 {
+	if a == 0 {
+		return nil
+	}
+
+	return []float64{-b / a}
+}
+
+// solveQuadratic returns the real roots of a*t^2 + b*t + c = 0.
+func solveQuadratic(a, b, c float64) []float64 {
+	if math.Abs(a) < 1e-12 {
+		return solveLinear(b, c)
+	}
+
+	discriminant := b*b - 4*a*c
+	switch {
+	case discriminant < 0:
+		return nil
+	case discriminant == 0:
+		return []float64{-b / (2 * a)}
+	}
+
+	// Avoid the cancellation in -b + sqrt(discriminant) when b is large.
+	q := -(b + math.Copysign(math.Sqrt(discriminant), b)) / 2
+	if q == 0 {
+		return []float64{0}
+	}
+
+	return []float64{q / a, c / q}
+}
+
+// inUnitInterval returns the values of ts that lie strictly between 0 and 1.
+func inUnitInterval(ts []float64) []float64 {
+	var result []float64
+
+	for _, t := range ts {
+		if t > 0 && t < 1 {
+			result = append(result, t)
+		}
+	}
+
+	return result
+}
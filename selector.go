@@ -0,0 +1,648 @@
+package svg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Query returns the first element, in document order, that matches the CSS
+// selector, or nil if none does. The element itself and its descendants are
+// searched and the element is the one :root matches.
+//
+// The selector may use type, universal, #id, .class and attribute selectors
+// with all the attribute operators, the descendant, child, next and
+// subsequent sibling combinators, and the pseudo-classes :root, :empty,
+// :first-child, :last-child, :only-child, :nth-child(), :nth-last-child(),
+// :first-of-type, :last-of-type, :only-of-type, :nth-of-type(),
+// :nth-last-of-type() and :not(). A namespace prefix is written as in CSS,
+// e.g. "xlink|href" matches the attribute "xlink:href".
+func (e *Element) Query(selector string) (*Element, error) {
+	matches, err := query(e, selector, true)
+	if err != nil || len(matches) == 0 {
+		return nil, err
+	}
+	return matches[0], nil
+}
+
+// QueryAll returns all elements that match the CSS selector in document
+// order. The search is the same as for Query.
+func (e *Element) QueryAll(selector string) ([]*Element, error) {
+	return query(e, selector, false)
+}
+
+func query(e *Element, selector string, first bool) ([]*Element, error) {
+	parser := &selectorParser{valueParser{raw: selector}}
+
+	selectors, err := parser.list(false)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid selector '%s': %s", selector, err)
+	}
+
+	var matches []*Element
+
+	var walk func(s *scope) bool
+	walk = func(s *scope) bool {
+		if matchAny(selectors, s) {
+			matches = append(matches, s.element)
+			if first {
+				return false
+			}
+		}
+
+		for i, child := range s.element.Children {
+			if !walk(&scope{element: child, parent: s, index: i}) {
+				return false
+			}
+		}
+		return true
+	}
+	walk(&scope{element: e})
+
+	return matches, nil
+}
+
+// scope is an element together with its place in the searched tree.
+type scope struct {
+	element *Element
+	parent  *scope
+	index   int
+}
+
+// siblings returns the children of the parent of the element, or just the
+// element at the root of the search.
+func (s *scope) siblings() []*Element {
+	if s.parent == nil {
+		return []*Element{s.element}
+	}
+	return s.parent.element.Children
+}
+
+// sibling returns the scope of the sibling at the index.
+func (s *scope) sibling(index int) *scope {
+	return &scope{element: s.siblings()[index], parent: s.parent, index: index}
+}
+
+// position returns the position of the element among its siblings that
+// match fn, counted from 1, from the start or from the end.
+func (s *scope) position(fn func(*Element) bool, fromEnd bool) int {
+	siblings := s.siblings()
+	index := 0
+	if s.parent != nil {
+		index = s.index
+	}
+
+	position := 1
+	if fromEnd {
+		for _, sibling := range siblings[index+1:] {
+			if fn(sibling) {
+				position++
+			}
+		}
+	} else {
+		for _, sibling := range siblings[:index] {
+			if fn(sibling) {
+				position++
+			}
+		}
+	}
+
+	return position
+}
+
+// filter is a simple selector, such as a type or a class selector.
+type filter func(s *scope) bool
+
+// compound is a sequence of simple selectors that all match one element.
+type compound []filter
+
+func (c compound) match(s *scope) bool {
+	for _, filter := range c {
+		if !filter(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// complexSelector is a sequence of compound selectors separated by the
+// combinators ' ', '>', '+' and '~'.
+type complexSelector struct {
+	compounds   []compound
+	combinators []byte
+}
+
+// match returns true if the compound selectors up to the index match with
+// the last one matching the element of the scope.
+func (c complexSelector) match(s *scope, index int) bool {
+	if !c.compounds[index].match(s) {
+		return false
+	}
+
+	if index == 0 {
+		return true
+	}
+
+	switch c.combinators[index-1] {
+	case ' ':
+		for parent := s.parent; parent != nil; parent = parent.parent {
+			if c.match(parent, index-1) {
+				return true
+			}
+		}
+
+	case '>':
+		return s.parent != nil && c.match(s.parent, index-1)
+
+	case '+':
+		return s.parent != nil && s.index > 0 &&
+			c.match(s.sibling(s.index-1), index-1)
+
+	case '~':
+		for i := s.index - 1; s.parent != nil && i >= 0; i-- {
+			if c.match(s.sibling(i), index-1) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchAny returns true if any of the selectors matches the element of the
+// scope.
+func matchAny(selectors []complexSelector, s *scope) bool {
+	for _, selector := range selectors {
+		if selector.match(s, len(selector.compounds)-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectorParser reads CSS selectors.
+type selectorParser struct {
+	valueParser
+}
+
+// list reads a comma separated list of selectors up to the end of the input,
+// or up to a closing parenthesis if the list is an argument.
+func (p *selectorParser) list(argument bool) ([]complexSelector, error) {
+	var selectors []complexSelector
+
+	for {
+		p.skipSpace()
+		selector, err := p.complex()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+
+		switch {
+		case p.peek() == ',':
+			p.pos++
+		case p.pos == len(p.raw) && !argument:
+			return selectors, nil
+		case p.peek() == ')' && argument:
+			return selectors, nil
+		default:
+			return nil, p.unexpected()
+		}
+	}
+}
+
+// complex reads compound selectors and the combinators between them.
+func (p *selectorParser) complex() (complexSelector, error) {
+	var selector complexSelector
+
+	for {
+		compound, err := p.compound()
+		if err != nil {
+			return selector, err
+		}
+		selector.compounds = append(selector.compounds, compound)
+
+		start := p.pos
+		p.skipSpace()
+
+		combinator := byte(' ')
+		switch c := p.peek(); {
+		case c == '>' || c == '+' || c == '~':
+			combinator = c
+			p.pos++
+			p.skipSpace()
+		case c == ',' || c == ')' || p.pos == len(p.raw):
+			return selector, nil
+		case p.pos == start:
+			return selector, p.unexpected()
+		}
+		selector.combinators = append(selector.combinators, combinator)
+	}
+}
+
+// compound reads an optional type selector followed by other simple
+// selectors.
+func (p *selectorParser) compound() (compound, error) {
+	var selector compound
+	start := p.pos
+
+	if c := p.peek(); c == '*' || c == '|' || isIdentChar(c) {
+		match, err := p.qualifiedName()
+		if err != nil {
+			return nil, err
+		}
+		selector = append(selector, func(s *scope) bool {
+			return match(s.element.Name)
+		})
+	}
+
+	for {
+		var next filter
+		var err error
+
+		switch p.peek() {
+		case '#':
+			p.pos++
+			next, err = p.attributeValue("id", "=")
+		case '.':
+			p.pos++
+			next, err = p.attributeValue("class", "~=")
+		case '[':
+			p.pos++
+			next, err = p.attribute()
+		case ':':
+			p.pos++
+			next, err = p.pseudoClass()
+		default:
+			if p.pos == start {
+				return nil, p.unexpected()
+			}
+			return selector, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+		selector = append(selector, next)
+	}
+}
+
+// qualifiedName reads a name with an optional namespace prefix, either of
+// which may be the wildcard '*', and returns a function that matches it.
+func (p *selectorParser) qualifiedName() (func(string) bool, error) {
+	prefix, local, hasPrefix := "", "", false
+
+	name, err := p.nameOrWildcard()
+	if err != nil && p.peek() != '|' {
+		return nil, err
+	}
+
+	// A vertical bar followed by an equals sign is an attribute operator.
+	if p.peek() == '|' && !strings.HasPrefix(p.raw[p.pos:], "|=") {
+		p.pos++
+		prefix, hasPrefix = name, true
+		if local, err = p.nameOrWildcard(); err != nil {
+			return nil, err
+		}
+	} else {
+		local = name
+	}
+
+	return func(name string) bool {
+		namePrefix, nameLocal := SplitName(name)
+		if !hasPrefix {
+			namePrefix, nameLocal = "", name
+		}
+
+		return (prefix == "*" || prefix == namePrefix) &&
+			(local == "*" || local == nameLocal)
+	}, nil
+}
+
+// nameOrWildcard reads an identifier or the wildcard '*'.
+func (p *selectorParser) nameOrWildcard() (string, error) {
+	if p.peek() == '*' {
+		p.pos++
+		return "*", nil
+	}
+	return p.ident()
+}
+
+// attribute reads an attribute selector after its opening bracket.
+func (p *selectorParser) attribute() (filter, error) {
+	p.skipSpace()
+	match, err := p.qualifiedName()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+
+	operator := ""
+	for _, op := range []string{"=", "~=", "|=", "^=", "$=", "*="} {
+		if strings.HasPrefix(p.raw[p.pos:], op) {
+			operator = op
+			p.pos += len(op)
+			break
+		}
+	}
+
+	var value string
+	if operator != "" {
+		p.skipSpace()
+		if c := p.peek(); c == '"' || c == '\'' {
+			value, err = p.quoted()
+		} else {
+			value, err = p.ident()
+		}
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+	}
+
+	ignoreCase := false
+	if c := p.peek(); c == 'i' || c == 'I' {
+		ignoreCase = true
+		p.pos++
+		p.skipSpace()
+	}
+
+	if p.peek() != ']' {
+		return nil, p.unexpected()
+	}
+	p.pos++
+
+	if ignoreCase {
+		value = strings.ToLower(value)
+	}
+
+	return func(s *scope) bool {
+		for name, actual := range s.element.Attributes {
+			if ignoreCase {
+				actual = strings.ToLower(actual)
+			}
+			if match(name) && compareAttribute(actual, operator, value) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// attributeValue reads an identifier and returns a filter that compares the
+// attribute to it, as in #id and .class selectors.
+func (p *selectorParser) attributeValue(name, operator string) (filter, error) {
+	value, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(s *scope) bool {
+		actual, ok := s.element.Attributes[name]
+		return ok && compareAttribute(actual, operator, value)
+	}, nil
+}
+
+// compareAttribute compares the value of an attribute using an operator of
+// attribute selectors. An empty operator only checks that it is present.
+func compareAttribute(actual, operator, value string) bool {
+	switch operator {
+	case "=":
+		return actual == value
+	case "~=":
+		for _, word := range strings.Fields(actual) {
+			if word == value {
+				return true
+			}
+		}
+		return false
+	case "|=":
+		return actual == value || strings.HasPrefix(actual, value+"-")
+	case "^=":
+		return value != "" && strings.HasPrefix(actual, value)
+	case "$=":
+		return value != "" && strings.HasSuffix(actual, value)
+	case "*=":
+		return value != "" && strings.Contains(actual, value)
+	}
+	return true
+}
+
+// pseudoClass reads a pseudo-class after its colon.
+func (p *selectorParser) pseudoClass() (filter, error) {
+	start := p.pos
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+
+	isElement := func(*Element) bool { return true }
+	sameType := func(s *scope) func(*Element) bool {
+		return func(e *Element) bool { return e.Name == s.element.Name }
+	}
+
+	switch name {
+	case "root":
+		return func(s *scope) bool { return s.parent == nil }, nil
+
+	case "empty":
+		return func(s *scope) bool {
+			return len(s.element.Children) == 0 && s.element.Content == "" &&
+				textContent(s.element.Nodes) == ""
+		}, nil
+
+	case "first-child":
+		return nthFilter(0, 1, false, nil), nil
+	case "last-child":
+		return nthFilter(0, 1, true, nil), nil
+	case "only-child":
+		return func(s *scope) bool {
+			return s.position(isElement, false) == 1 &&
+				s.position(isElement, true) == 1
+		}, nil
+
+	case "first-of-type":
+		return nthFilter(0, 1, false, sameType), nil
+	case "last-of-type":
+		return nthFilter(0, 1, true, sameType), nil
+	case "only-of-type":
+		return func(s *scope) bool {
+			return s.position(sameType(s), false) == 1 &&
+				s.position(sameType(s), true) == 1
+		}, nil
+
+	case "nth-child", "nth-last-child", "nth-of-type", "nth-last-of-type":
+		a, b, err := p.nthArgument()
+		if err != nil {
+			return nil, err
+		}
+
+		var of func(*scope) func(*Element) bool
+		if strings.HasSuffix(name, "of-type") {
+			of = sameType
+		}
+		return nthFilter(a, b, strings.HasPrefix(name, "nth-last"), of), nil
+
+	case "not":
+		if p.peek() != '(' {
+			return nil, p.unexpected()
+		}
+		p.pos++
+
+		selectors, err := p.list(true)
+		if err != nil {
+			return nil, err
+		}
+		p.pos++
+
+		return func(s *scope) bool { return !matchAny(selectors, s) }, nil
+	}
+
+	return nil, p.errorf(start, "Unsupported pseudo-class '%s'", name)
+}
+
+// nthFilter returns a filter that matches elements at the positions a*n+b
+// among their siblings, or among the siblings that of returns true for.
+func nthFilter(a, b int, fromEnd bool,
+	of func(*scope) func(*Element) bool) filter {
+
+	return func(s *scope) bool {
+		fn := func(*Element) bool { return true }
+		if of != nil {
+			fn = of(s)
+		}
+
+		n := s.position(fn, fromEnd) - b
+		if a == 0 {
+			return n == 0
+		}
+		return n%a == 0 && n/a >= 0
+	}
+}
+
+// nthArgument reads the parenthesized argument of the nth pseudo-classes in
+// the form a*n+b, "odd" or "even".
+func (p *selectorParser) nthArgument() (int, int, error) {
+	if p.peek() != '(' {
+		return 0, 0, p.unexpected()
+	}
+	p.pos++
+
+	start := p.pos
+	end := strings.IndexByte(p.raw[start:], ')')
+	if end < 0 {
+		p.pos = len(p.raw)
+		return 0, 0, p.unexpected()
+	}
+	p.pos += end + 1
+
+	argument := strings.ToLower(strings.Join(
+		strings.Fields(p.raw[start:start+end]), ""))
+
+	switch argument {
+	case "odd":
+		return 2, 1, nil
+	case "even":
+		return 2, 0, nil
+	}
+
+	invalid := p.errorf(start, "Invalid argument '%s'", p.raw[start:start+end])
+
+	i := strings.IndexByte(argument, 'n')
+	if i < 0 {
+		b, err := strconv.Atoi(argument)
+		if err != nil {
+			return 0, 0, invalid
+		}
+		return 0, b, nil
+	}
+
+	var a, b int
+	switch coefficient := argument[:i]; coefficient {
+	case "", "+":
+		a = 1
+	case "-":
+		a = -1
+	default:
+		var err error
+		if a, err = strconv.Atoi(coefficient); err != nil {
+			return 0, 0, invalid
+		}
+	}
+
+	if offset := argument[i+1:]; offset != "" {
+		var err error
+		if offset[0] != '+' && offset[0] != '-' {
+			return 0, 0, invalid
+		}
+		if b, err = strconv.Atoi(offset); err != nil {
+			return 0, 0, invalid
+		}
+	}
+
+	return a, b, nil
+}
+
+// ident reads an identifier. A backslash escapes the character after it.
+func (p *selectorParser) ident() (string, error) {
+	var name []byte
+
+	for p.pos < len(p.raw) {
+		c := p.raw[p.pos]
+
+		if c == '\\' && p.pos+1 < len(p.raw) {
+			name = append(name, p.raw[p.pos+1])
+			p.pos += 2
+			continue
+		}
+
+		if !isIdentChar(c) {
+			break
+		}
+		name = append(name, c)
+		p.pos++
+	}
+
+	if len(name) == 0 {
+		return "", p.unexpected()
+	}
+
+	return string(name), nil
+}
+
+// quoted reads a quoted string. A backslash escapes the character after it.
+func (p *selectorParser) quoted() (string, error) {
+	quote := p.raw[p.pos]
+	p.pos++
+
+	var value []byte
+	for p.pos < len(p.raw) && p.raw[p.pos] != quote {
+		if p.raw[p.pos] == '\\' && p.pos+1 < len(p.raw) {
+			p.pos++
+		}
+		value = append(value, p.raw[p.pos])
+		p.pos++
+	}
+
+	if p.pos == len(p.raw) {
+		return "", p.unexpected()
+	}
+	p.pos++
+
+	return string(value), nil
+}
+
+// unexpected returns an error about the current character.
+func (p *selectorParser) unexpected() error {
+	if p.pos == len(p.raw) {
+		return p.errorf(p.pos, "Unexpected end of selector")
+	}
+	return p.errorf(p.pos, "Unexpected symbol '%c'", p.raw[p.pos])
+}
+
+// isIdentChar returns true if the character can be part of an identifier.
+// Bytes of multi-byte characters are always accepted.
+func isIdentChar(c byte) bool {
+	return isLetter(c) || c >= '0' && c <= '9' || c == '-' || c == '_' ||
+		c == '\\' || c >= 0x80
+}
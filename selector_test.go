@@ -0,0 +1,178 @@
+package svg_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+const queryDocument = `<svg id="root" xmlns:xlink="http://www.w3.org/1999/xlink">
+	<g id="a" class="icon large" fill="red">
+		<rect id="a1" lang="en-US"/>
+		<circle id="a2" data-name="big-dot"/>
+		<rect id="a3"/>
+		<text id="a4">Hi</text>
+	</g>
+	<g id="b" class="icon">
+		<use id="b1" xlink:href="#a1"/>
+		<g id="b2"></g>
+	</g>
+	<path id="c" d="M 0 0"/>
+</svg>`
+
+func TestElementQueryAll(t *testing.T) {
+	tests := []struct {
+		selector string
+		expected string
+	}{
+		{selector: "rect", expected: "a1 a3"},
+		{selector: "*", expected: "root a a1 a2 a3 a4 b b1 b2 c"},
+		{selector: "#b", expected: "b"},
+		{selector: ".icon", expected: "a b"},
+		{selector: "g.icon.large", expected: "a"},
+		{selector: "[fill]", expected: "a"},
+		{selector: "[class=icon]", expected: "b"},
+		{selector: "[class~=large]", expected: "a"},
+		{selector: "[lang|=en]", expected: "a1"},
+		{selector: "[data-name^='big']", expected: "a2"},
+		{selector: `[data-name$="dot"]`, expected: "a2"},
+		{selector: "[data-name*=g-d]", expected: "a2"},
+		{selector: "[CLASS]", expected: ""},
+		{selector: "[class=ICON i]", expected: "b"},
+		{selector: "[xlink|href]", expected: "b1"},
+		{selector: "[*|href='#a1']", expected: "b1"},
+		{selector: "[href]", expected: ""},
+		{selector: "svg g", expected: "a b b2"},
+		{selector: "svg > g", expected: "a b"},
+		{selector: "g > g", expected: "b2"},
+		{selector: "rect + circle", expected: "a2"},
+		{selector: "#a1 ~ rect", expected: "a3"},
+		{selector: "#a1~*", expected: "a2 a3 a4"},
+		{selector: "g rect, path", expected: "a1 a3 c"},
+		{selector: ":root", expected: "root"},
+		{selector: "g:empty", expected: "b2"},
+		{selector: "text:empty", expected: ""},
+		{selector: "g > :first-child", expected: "a1 b1"},
+		{selector: "g > :last-child", expected: "a4 b2"},
+		{selector: ":only-child", expected: "root"},
+		{selector: "rect:first-of-type", expected: "a1"},
+		{selector: "rect:last-of-type", expected: "a3"},
+		{selector: "#a > :only-of-type", expected: "a2 a4"},
+		{selector: "#a > :nth-child(2n+1)", expected: "a1 a3"},
+		{selector: "#a > :nth-child(even)", expected: "a2 a4"},
+		{selector: "#a > :nth-child(-n + 2)", expected: "a1 a2"},
+		{selector: "#a > :nth-last-child(1)", expected: "a4"},
+		{selector: "rect:nth-of-type(2)", expected: "a3"},
+		{selector: "g:nth-last-of-type(2)", expected: "a"},
+		{selector: "#a > :not(rect, text)", expected: "a2"},
+		{selector: "g:not(.icon)", expected: "b2"},
+		{selector: "svg|rect", expected: ""},
+		{selector: "*|use", expected: "b1"},
+	}
+
+	root, err := New(strings.NewReader(queryDocument))
+	if err != nil {
+		t.Fatalf("New: unexpected error: %s", err)
+	}
+
+	for _, test := range tests {
+		t.Run(test.selector, func(t *testing.T) {
+			elements, err := root.QueryAll(test.selector)
+			if err != nil {
+				t.Fatalf("QueryAll: unexpected error: %s", err)
+			}
+
+			var ids []string
+			for _, element := range elements {
+				ids = append(ids, element.Attributes["id"])
+			}
+
+			if actual := strings.Join(ids, " "); actual != test.expected {
+				t.Fatalf("QueryAll: expected %s, actual %s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestElementQuery(t *testing.T) {
+	root, err := New(strings.NewReader(queryDocument))
+	if err != nil {
+		t.Fatalf("New: unexpected error: %s", err)
+	}
+
+	element, err := root.Query("g rect")
+	if err != nil {
+		t.Fatalf("Query: unexpected error: %s", err)
+	}
+	if element == nil || element.Attributes["id"] != "a1" {
+		t.Fatalf("Query: expected element a1, actual %v", element)
+	}
+
+	element, err = root.Query("ellipse")
+	if err != nil {
+		t.Fatalf("Query: unexpected error: %s", err)
+	}
+	if element != nil {
+		t.Fatalf("Query: expected nil, actual %v", element)
+	}
+}
+
+func TestElementQueryErrors(t *testing.T) {
+	tests := []struct {
+		selector string
+		expected string
+	}{
+		{
+			selector: "",
+			expected: "Invalid selector '': Unexpected end of selector at line 1, column 1",
+		},
+		{
+			selector: "g >",
+			expected: "Invalid selector 'g >': Unexpected end of selector at line 1, column 4",
+		},
+		{
+			selector: "g$",
+			expected: "Invalid selector 'g$': Unexpected symbol '$' at line 1, column 2",
+		},
+		{
+			selector: "[fill",
+			expected: "Invalid selector '[fill': Unexpected end of selector at line 1, column 6",
+		},
+		{
+			selector: "[fill='red]",
+			expected: "Invalid selector '[fill='red]': Unexpected end of selector at line 1, column 12",
+		},
+		{
+			selector: "g:hover",
+			expected: "Invalid selector 'g:hover': Unsupported pseudo-class 'hover' at line 1, column 3",
+		},
+		{
+			selector: ":nth-child(2x)",
+			expected: "Invalid selector ':nth-child(2x)': Invalid argument '2x' at line 1, column 12",
+		},
+		{
+			selector: ":not(g",
+			expected: "Invalid selector ':not(g': Unexpected end of selector at line 1, column 7",
+		},
+		{
+			selector: "g)",
+			expected: "Invalid selector 'g)': Unexpected symbol ')' at line 1, column 2",
+		},
+	}
+
+	root := &Element{Name: "svg"}
+
+	for _, test := range tests {
+		t.Run(test.selector, func(t *testing.T) {
+			_, err := root.QueryAll(test.selector)
+			if err == nil {
+				t.Fatalf("QueryAll: expected error, actual nil")
+			}
+
+			if err.Error() != test.expected {
+				t.Fatalf("QueryAll: expected %s, actual %s", test.expected, err)
+			}
+		})
+	}
+}
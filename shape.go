@@ -0,0 +1,230 @@
+package svg
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+)
+
+// shapeAttributes maps the path and basic shape elements to the attributes
+// that define their geometry.
+var shapeAttributes = map[string][]string{
+	"path":     {"d"},
+	"rect":     {"x", "y", "width", "height", "rx", "ry"},
+	"circle":   {"cx", "cy", "r"},
+	"ellipse":  {"cx", "cy", "rx", "ry"},
+	"line":     {"x1", "y1", "x2", "y2"},
+	"polyline": {"points"},
+	"polygon":  {"points"},
+}
+
+// ToPath returns the geometry of a path or a basic shape element as a path.
+// Returns an error for other elements and for geometry attributes that cannot
+// be parsed, including lengths relative to the viewport. Shapes that are not
+// rendered, such as a rect without a width, result in an empty path.
+func (e *Element) ToPath() (*Path, error) {
+	if _, ok := shapeAttributes[e.Name]; !ok {
+		return nil, fmt.Errorf("Element %s is not a shape", e.Name)
+	}
+
+	if e.Name == "path" {
+		return NewPath(e.Attributes["d"])
+	}
+
+	if e.Name == "polyline" || e.Name == "polygon" {
+		points, err := parsePoints(e.Attributes["points"])
+		if err != nil {
+			return nil, err
+		}
+		return polylinePath(points, e.Name == "polygon"), nil
+	}
+
+	lengths, err := shapeLengths(e)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.Name {
+	case "rect":
+		rx, ry := cornerRadii(lengths)
+		return rectPath(lengths["x"], lengths["y"],
+			lengths["width"], lengths["height"], rx, ry), nil
+
+	case "circle":
+		return ellipsePath(lengths["cx"], lengths["cy"],
+			lengths["r"], lengths["r"]), nil
+
+	case "ellipse":
+		return ellipsePath(lengths["cx"], lengths["cy"],
+			lengths["rx"], lengths["ry"]), nil
+	}
+
+	return &Path{Commands: []*PathCommand{
+		{Symbol: "M", Params: []float64{lengths["x1"], lengths["y1"]}},
+		{Symbol: "L", Params: []float64{lengths["x2"], lengths["y2"]}},
+	}}, nil
+}
+
+// shapeLengths parses the geometry attributes of a basic shape that are
+// present.
+func shapeLengths(e *Element) (map[string]float64, error) {
+	lengths := map[string]float64{}
+
+	for _, name := range shapeAttributes[e.Name] {
+		raw, ok := e.Attributes[name]
+		if !ok {
+			continue
+		}
+
+		length, err := parseLength(raw)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %s of %s: %s", name, e.Name, err)
+		}
+		lengths[name] = length
+	}
+
+	return lengths, nil
+}
+
+// cornerRadii returns the radii of the rounded corners of a rect element. A
+// missing radius is the same as the other one and both are limited to half
+// of the size of the rect.
+func cornerRadii(lengths map[string]float64) (float64, float64) {
+	rx, hasRX := lengths["rx"]
+	ry, hasRY := lengths["ry"]
+
+	switch {
+	case !hasRX && hasRY:
+		rx = ry
+	case hasRX && !hasRY:
+		ry = rx
+	}
+
+	rx = math.Min(math.Max(rx, 0), lengths["width"]/2)
+	ry = math.Min(math.Max(ry, 0), lengths["height"]/2)
+
+	return rx, ry
+}
+
+// rectPath returns the path of a rectangle with rounded corners.
+func rectPath(x, y, width, height, rx, ry float64) *Path {
+	if width <= 0 || height <= 0 {
+		return &Path{}
+	}
+
+	command := func(symbol string, params ...float64) *PathCommand {
+		return &PathCommand{Symbol: symbol, Params: params}
+	}
+
+	if rx == 0 || ry == 0 {
+		return &Path{Commands: []*PathCommand{
+			command("M", x, y),
+			command("H", x+width),
+			command("V", y+height),
+			command("H", x),
+			command("Z"),
+		}}
+	}
+
+	return &Path{Commands: []*PathCommand{
+		command("M", x+rx, y),
+		command("H", x+width-rx),
+		command("A", rx, ry, 0, 0, 1, x+width, y+ry),
+		command("V", y+height-ry),
+		command("A", rx, ry, 0, 0, 1, x+width-rx, y+height),
+		command("H", x+rx),
+		command("A", rx, ry, 0, 0, 1, x, y+height-ry),
+		command("V", y+ry),
+		command("A", rx, ry, 0, 0, 1, x+rx, y),
+		command("Z"),
+	}}
+}
+
+// ellipsePath returns the path of an ellipse drawn as two arcs.
+func ellipsePath(cx, cy, rx, ry float64) *Path {
+	if rx <= 0 || ry <= 0 {
+		return &Path{}
+	}
+
+	return &Path{Commands: []*PathCommand{
+		{Symbol: "M", Params: []float64{cx + rx, cy}},
+		{Symbol: "A", Params: []float64{rx, ry, 0, 0, 1, cx - rx, cy}},
+		{Symbol: "A", Params: []float64{rx, ry, 0, 0, 1, cx + rx, cy}},
+		{Symbol: "Z"},
+	}}
+}
+
+// polylinePath returns the path through the points, closed for a polygon.
+func polylinePath(points []Point, closed bool) *Path {
+	path := &Path{}
+
+	for i, point := range points {
+		symbol := "L"
+		if i == 0 {
+			symbol = "M"
+		}
+		path.Commands = append(path.Commands, &PathCommand{
+			Symbol: symbol, Params: []float64{point.X, point.Y}})
+	}
+
+	if closed && len(points) > 0 {
+		path.Commands = append(path.Commands, &PathCommand{Symbol: "Z"})
+	}
+
+	return path
+}
+
+// parsePoints parses the value of a points attribute. A trailing odd
+// coordinate is ignored, as the shape is rendered up to it.
+func parsePoints(raw string) ([]Point, error) {
+	numbers, err := parseNumbers(raw)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid points '%s'", raw)
+	}
+
+	var points []Point
+	for i := 0; i+1 < len(numbers); i += 2 {
+		points = append(points, Point{numbers[i], numbers[i+1]})
+	}
+
+	return points, nil
+}
+
+// formatPoints writes points as the value of a points attribute.
+func formatPoints(points []Point) string {
+	buf := &bytes.Buffer{}
+
+	for i, point := range points {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(formatNumber(point.X, -1, false))
+		buf.WriteByte(',')
+		buf.WriteString(formatNumber(point.Y, -1, false))
+	}
+
+	return buf.String()
+}
+
+// parseNumbers parses a list of numbers separated by commas or whitespace.
+func parseNumbers(raw string) ([]float64, error) {
+	parser := &valueParser{raw: raw}
+	numbers := []float64{}
+
+	parser.skipSpace()
+	for parser.pos < len(parser.raw) {
+		if len(numbers) > 0 && parser.peek() == ',' {
+			parser.pos++
+			parser.skipSpace()
+		}
+
+		number, err := parser.number()
+		if err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, number)
+		parser.skipSpace()
+	}
+
+	return numbers, nil
+}
@@ -0,0 +1,150 @@
+package svg_test
+
+import (
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+func TestElementToPath(t *testing.T) {
+	tests := []struct {
+		description string
+		element     *Element
+		expected    string
+	}{
+		{
+			description: "path",
+			element: &Element{
+				Name:       "path",
+				Attributes: map[string]string{"d": "m 1 2 l 3 4"},
+			},
+			expected: "m 1 2 l 3 4",
+		},
+		{
+			description: "rect",
+			element: &Element{
+				Name: "rect",
+				Attributes: map[string]string{
+					"x": "10", "y": "20", "width": "30", "height": "40",
+				},
+			},
+			expected: "M 10 20 H 40 V 60 H 10 Z",
+		},
+		{
+			description: "rounded rect with one radius",
+			element: &Element{
+				Name: "rect",
+				Attributes: map[string]string{
+					"width": "30", "height": "40", "ry": "20",
+				},
+			},
+			expected: "M 15 0 H 15 A 15 20 0 0 1 30 20 V 20 " +
+				"A 15 20 0 0 1 15 40 H 15 A 15 20 0 0 1 0 20 V 20 " +
+				"A 15 20 0 0 1 15 0 Z",
+		},
+		{
+			description: "rect without size",
+			element: &Element{
+				Name:       "rect",
+				Attributes: map[string]string{"width": "10"},
+			},
+			expected: "",
+		},
+		{
+			description: "circle",
+			element: &Element{
+				Name:       "circle",
+				Attributes: map[string]string{"cx": "10", "cy": "10", "r": "1in"},
+			},
+			expected: "M 106 10 A 96 96 0 0 1 -86 10 A 96 96 0 0 1 106 10 Z",
+		},
+		{
+			description: "ellipse",
+			element: &Element{
+				Name:       "ellipse",
+				Attributes: map[string]string{"rx": "10", "ry": "5"},
+			},
+			expected: "M 10 0 A 10 5 0 0 1 -10 0 A 10 5 0 0 1 10 0 Z",
+		},
+		{
+			description: "line",
+			element: &Element{
+				Name:       "line",
+				Attributes: map[string]string{"x1": "1", "x2": "3", "y2": "4"},
+			},
+			expected: "M 1 0 L 3 4",
+		},
+		{
+			description: "polyline with odd coordinates",
+			element: &Element{
+				Name:       "polyline",
+				Attributes: map[string]string{"points": "0,0 10-10 20 5 7"},
+			},
+			expected: "M 0 0 L 10 -10 L 20 5",
+		},
+		{
+			description: "polygon",
+			element: &Element{
+				Name:       "polygon",
+				Attributes: map[string]string{"points": "0,0 10,0 10,10"},
+			},
+			expected: "M 0 0 L 10 0 L 10 10 Z",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			path, err := test.element.ToPath()
+			if err != nil {
+				t.Fatalf("Element: unexpected error: %s", err)
+			}
+
+			if actual := path.String(); actual != test.expected {
+				t.Errorf("Element: expected %q, actual %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestElementToPathErrors(t *testing.T) {
+	tests := []struct {
+		description   string
+		element       *Element
+		expectedError string
+	}{
+		{
+			description:   "not a shape",
+			element:       &Element{Name: "g"},
+			expectedError: "Element g is not a shape",
+		},
+		{
+			description: "relative length",
+			element: &Element{
+				Name:       "circle",
+				Attributes: map[string]string{"r": "50%"},
+			},
+			expectedError: "Invalid r of circle: Unsupported length '50%'",
+		},
+		{
+			description: "invalid points",
+			element: &Element{
+				Name:       "polygon",
+				Attributes: map[string]string{"points": "0,0 1,x"},
+			},
+			expectedError: "Invalid points '0,0 1,x'",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			path, err := test.element.ToPath()
+			if path != nil {
+				t.Fatalf("Element: expected nil, actual %v", path)
+			}
+
+			if err == nil || err.Error() != test.expectedError {
+				t.Errorf("Element: expected %v, actual %v", test.expectedError, err)
+			}
+		})
+	}
+}
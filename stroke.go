@@ -0,0 +1,389 @@
+package svg
+
+import "math"
+
+// LineJoin is the shape of the corners of a stroke, as set by the
+// stroke-linejoin property.
+type LineJoin int
+
+// Shapes of the corners of a stroke.
+const (
+	MiterJoin LineJoin = iota
+	RoundJoin
+	BevelJoin
+)
+
+// LineCap is the shape of the ends of an open stroke, as set by the
+// stroke-linecap property.
+type LineCap int
+
+// Shapes of the ends of a stroke.
+const (
+	ButtCap LineCap = iota
+	RoundCap
+	SquareCap
+)
+
+// lineJoins and lineCaps map the values of the stroke-linejoin and
+// stroke-linecap properties to their shapes.
+var (
+	lineJoins = map[string]LineJoin{
+		"miter": MiterJoin, "round": RoundJoin, "bevel": BevelJoin,
+	}
+	lineCaps = map[string]LineCap{
+		"butt": ButtCap, "round": RoundCap, "square": SquareCap,
+	}
+)
+
+// Stroke returns the outline of the stroke of the path with the given width,
+// joins, caps and miter limit, as a path to be filled. The outline is made of
+// closed polygons that do not overlap, built the same way as by Union, so it
+// has the same area with either fill rule. Curves, arcs, round joins and
+// round caps are approximated by lines within a hundredth of the width.
+func (p *Path) Stroke(width float64, join LineJoin, cap LineCap,
+	miterLimit float64) *Path {
+
+	outline := &Path{}
+	if width <= 0 {
+		return outline
+	}
+
+	tolerance := width / 100
+	polygons := strokePolylines(p.polylines(tolerance), width, join, cap,
+		miterLimit, tolerance)
+
+	for _, polygon := range polygons {
+		outline.Commands = append(outline.Commands,
+			polylinePath(polygon, true).Commands...)
+	}
+
+	return outline.Union(&Path{}, NonZero)
+}
+
+// stroker builds the outline of strokes as a set of polygons: one for each
+// segment, join and cap. The polygons overlap and all have a positive signed
+// area, so that their union is filled by the nonzero rule.
+type stroker struct {
+	halfWidth  float64
+	join       LineJoin
+	cap        LineCap
+	miterLimit float64
+	tolerance  float64
+
+	polygons [][]Point
+}
+
+// strokePolylines returns the polygons that make up the outline of the
+// polylines stroked with the given properties. Curved parts are approximated
+// within the tolerance.
+func strokePolylines(polylines []polyline, width float64, join LineJoin,
+	cap LineCap, miterLimit, tolerance float64) [][]Point {
+
+	s := &stroker{
+		halfWidth:  width / 2,
+		join:       join,
+		cap:        cap,
+		miterLimit: miterLimit,
+		tolerance:  tolerance,
+	}
+
+	if width > 0 {
+		for _, polyline := range polylines {
+			s.polyline(polyline)
+		}
+	}
+
+	return s.polygons
+}
+
+func (s *stroker) polyline(l polyline) {
+	// A subpath made of a moveto alone is not stroked.
+	if len(l.points) < 2 && !l.closed {
+		return
+	}
+
+	var points []Point
+	for i, point := range l.points {
+		if i == 0 || point != points[len(points)-1] {
+			points = append(points, point)
+		}
+	}
+
+	if l.closed && len(points) > 1 && points[0] == points[len(points)-1] {
+		points = points[:len(points)-1]
+	}
+
+	// Zero length subpaths are drawn as a dot by round and square caps.
+	if len(points) == 1 {
+		s.dot(points[0])
+		return
+	}
+
+	count := len(points) - 1
+	if l.closed {
+		count = len(points)
+	}
+
+	for i := 0; i < count; i++ {
+		s.segment(points[i], points[(i+1)%len(points)])
+	}
+
+	for i := 1; i < len(points)-1; i++ {
+		s.corner(points[i-1], points[i], points[i+1])
+	}
+
+	if l.closed {
+		last := len(points) - 1
+		s.corner(points[last-1], points[last], points[0])
+		s.corner(points[last], points[0], points[1])
+		return
+	}
+
+	s.end(points[0], points[1])
+	s.end(points[len(points)-1], points[len(points)-2])
+}
+
+// segment adds the rectangle covered by the stroke of a line.
+func (s *stroker) segment(from, to Point) {
+	offset := normal(from, to).mul(s.halfWidth)
+
+	s.add([]Point{from.add(offset), to.add(offset), to.sub(offset),
+		from.sub(offset)})
+}
+
+// corner adds the join between the lines from a to b and from b to c.
+func (s *stroker) corner(a, b, c Point) {
+	in, out := unit(b.sub(a)), unit(c.sub(b))
+
+	cross := in.X*out.Y - in.Y*out.X
+	dot := in.dot(out)
+	if math.Abs(cross) < 1e-12 && dot > 0 {
+		return
+	}
+
+	// The join is on the outer side of the turn.
+	side := s.halfWidth
+	if cross > 0 {
+		side = -side
+	}
+
+	n0, n1 := normal(a, b), normal(b, c)
+	p0, p1 := b.add(n0.mul(side)), b.add(n1.mul(side))
+
+	if s.join == RoundJoin {
+		start := math.Atan2(p0.Y-b.Y, p0.X-b.X)
+		s.wedge(b, start, math.Atan2(n0.X*n1.Y-n0.Y*n1.X, n0.dot(n1)))
+		return
+	}
+
+	// The ratio of the miter length to the stroke width is one over the
+	// sine of half of the angle between the lines.
+	if s.join == MiterJoin && dot > -1 {
+		ratio := 1 / math.Sqrt((1+dot)/2)
+		if ratio <= s.miterLimit {
+			tip := b.add(unit(n0.add(n1)).mul(side * ratio))
+			s.add([]Point{b, p0, tip, p1})
+			return
+		}
+	}
+
+	s.add([]Point{b, p0, p1})
+}
+
+// end adds the cap at the end point of the line from the other point.
+func (s *stroker) end(point, other Point) {
+	switch s.cap {
+	case RoundCap:
+		// A half circle from one side of the stroke to the other, through
+		// the direction of the line.
+		offset := normal(other, point)
+		s.wedge(point, math.Atan2(offset.Y, offset.X), -math.Pi)
+
+	case SquareCap:
+		direction := unit(point.sub(other)).mul(s.halfWidth)
+		offset := normal(other, point).mul(s.halfWidth)
+
+		s.add([]Point{point.add(offset), point.add(offset).add(direction),
+			point.sub(offset).add(direction), point.sub(offset)})
+	}
+}
+
+// dot adds the caps of a zero length subpath at the point.
+func (s *stroker) dot(point Point) {
+	switch s.cap {
+	case RoundCap:
+		s.add(circlePolygon(point, s.halfWidth, s.tolerance))
+
+	case SquareCap:
+		h := s.halfWidth
+		s.add([]Point{{point.X - h, point.Y - h}, {point.X + h, point.Y - h},
+			{point.X + h, point.Y + h}, {point.X - h, point.Y + h}})
+	}
+}
+
+// wedge adds the sector of the circle with the width of the stroke around the
+// center that starts at an angle and spans the sweep angle, in radians.
+func (s *stroker) wedge(center Point, start, sweep float64) {
+	count := int(math.Ceil(float64(circleSegments(s.halfWidth, s.tolerance)) *
+		math.Abs(sweep) / (2 * math.Pi)))
+	if count < 1 {
+		count = 1
+	}
+
+	polygon := []Point{center}
+	for i := 0; i <= count; i++ {
+		sin, cos := math.Sincos(start + sweep*float64(i)/float64(count))
+		polygon = append(polygon,
+			Point{center.X + s.halfWidth*cos, center.Y + s.halfWidth*sin})
+	}
+
+	s.add(polygon)
+}
+
+// add adds the polygon, oriented to have a positive signed area. Polygons
+// without an area are left out.
+func (s *stroker) add(polygon []Point) {
+	area := signedArea(polygon)
+	if area == 0 {
+		return
+	}
+
+	if area < 0 {
+		for i, j := 0, len(polygon)-1; i < j; i, j = i+1, j-1 {
+			polygon[i], polygon[j] = polygon[j], polygon[i]
+		}
+	}
+
+	s.polygons = append(s.polygons, polygon)
+}
+
+// circlePolygon returns a regular polygon that approximates the circle
+// within the tolerance.
+func circlePolygon(center Point, radius, tolerance float64) []Point {
+	count := circleSegments(radius, tolerance)
+	points := make([]Point, count)
+	for i := range points {
+		sin, cos := math.Sincos(2 * math.Pi * float64(i) / float64(count))
+		points[i] = Point{center.X + radius*cos, center.Y + radius*sin}
+	}
+
+	return points
+}
+
+// circleSegments returns the number of sides of a regular polygon that
+// approximates a circle of the radius within the tolerance.
+func circleSegments(radius, tolerance float64) int {
+	if tolerance >= radius {
+		return 8
+	}
+
+	count := math.Max(8, math.Ceil(math.Pi/math.Acos(1-tolerance/radius)))
+	return int(math.Min(count, maxArcSegments))
+}
+
+// dashPolylines splits the polylines into the dashes given by the lengths of
+// the dash pattern and its offset. Returns the polylines unchanged for a
+// pattern that does not draw dashes.
+func dashPolylines(polylines []polyline, pattern []float64,
+	offset float64) []polyline {
+
+	var total float64
+	for _, length := range pattern {
+		if length < 0 {
+			return polylines
+		}
+		total += length
+	}
+
+	if total == 0 {
+		return polylines
+	}
+
+	if len(pattern)%2 == 1 {
+		pattern = append(pattern, pattern...)
+		total *= 2
+	}
+
+	// Find where in the pattern the start of each subpath falls.
+	offset = math.Mod(math.Mod(offset, total)+total, total)
+	first := 0
+	for offset >= pattern[first] {
+		offset -= pattern[first]
+		first = (first + 1) % len(pattern)
+	}
+
+	var dashes []polyline
+
+	for _, l := range polylines {
+		points := l.points
+		if len(points) < 2 {
+			continue
+		}
+
+		index, remaining := first, pattern[first]-offset
+		var dash []Point
+		if index%2 == 0 {
+			dash = []Point{points[0]}
+		}
+
+		for i := 1; i < len(points); i++ {
+			from, to := points[i-1], points[i]
+			length := to.sub(from).length()
+
+			position := 0.0
+			for length-position > remaining {
+				position += remaining
+				point := from.lerp(to, position/length)
+
+				if index%2 == 0 {
+					dashes = append(dashes, polyline{points: append(dash, point)})
+					dash = nil
+				} else {
+					dash = []Point{point}
+				}
+
+				index = (index + 1) % len(pattern)
+				remaining = pattern[index]
+			}
+
+			remaining -= length - position
+			if index%2 == 0 {
+				dash = append(dash, to)
+			}
+		}
+
+		if index%2 == 0 && len(dash) > 1 {
+			dashes = append(dashes, polyline{points: dash})
+		}
+	}
+
+	return dashes
+}
+
+// normal returns the unit vector perpendicular to the line from a to b.
+func normal(a, b Point) Point {
+	d := unit(b.sub(a))
+	return Point{-d.Y, d.X}
+}
+
+// unit returns the vector scaled to a length of one, or the zero vector.
+func unit(p Point) Point {
+	length := p.length()
+	if length == 0 {
+		return Point{}
+	}
+	return p.mul(1 / length)
+}
+
+// signedArea returns the area of the polygon, positive if it runs clockwise
+// in the coordinate system of SVG, where the y-axis points down.
+func signedArea(polygon []Point) float64 {
+	var area float64
+
+	for i, p := range polygon {
+		q := polygon[(i+1)%len(polygon)]
+		area += p.X*q.Y - q.X*p.Y
+	}
+
+	return area / 2
+}
@@ -0,0 +1,219 @@
+package svg_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+func TestPathStroke(t *testing.T) {
+	tests := []struct {
+		description string
+		rawPath     string
+		width       float64
+		join        LineJoin
+		cap         LineCap
+		miterLimit  float64
+		subpaths    int
+		bounds      Rect
+	}{
+		{
+			description: "butt cap",
+			rawPath:     "M 0 0 H 10",
+			width:       2,
+			cap:         ButtCap,
+			subpaths:    1,
+			bounds:      Rect{Point{0, -1}, Point{10, 1}},
+		},
+		{
+			description: "square cap",
+			rawPath:     "M 0 0 H 10",
+			width:       2,
+			cap:         SquareCap,
+			subpaths:    1,
+			bounds:      Rect{Point{-1, -1}, Point{11, 1}},
+		},
+		{
+			description: "round cap",
+			rawPath:     "M 0 0 H 10",
+			width:       2,
+			cap:         RoundCap,
+			subpaths:    1,
+			bounds:      Rect{Point{-1, -1}, Point{11, 1}},
+		},
+		{
+			description: "miter join",
+			rawPath:     "M 0 10 L 5 0 L 10 10",
+			width:       2,
+			join:        MiterJoin,
+			miterLimit:  4,
+			subpaths:    1,
+			bounds:      Rect{Point{-0.894, -2.236}, Point{10.894, 10.447}},
+		},
+		{
+			description: "miter join over the limit",
+			rawPath:     "M 0 10 L 5 0 L 10 10",
+			width:       2,
+			join:        MiterJoin,
+			miterLimit:  2,
+			subpaths:    1,
+			bounds:      Rect{Point{-0.894, -0.447}, Point{10.894, 10.447}},
+		},
+		{
+			description: "bevel join",
+			rawPath:     "M 0 10 L 5 0 L 10 10",
+			width:       2,
+			join:        BevelJoin,
+			subpaths:    1,
+			bounds:      Rect{Point{-0.894, -0.447}, Point{10.894, 10.447}},
+		},
+		{
+			description: "round join",
+			rawPath:     "M 0 10 L 5 0 L 10 10",
+			width:       2,
+			join:        RoundJoin,
+			subpaths:    1,
+			bounds:      Rect{Point{-0.894, -1}, Point{10.894, 10.447}},
+		},
+		{
+			description: "closed subpath",
+			rawPath:     "M 0 0 H 10 V 10 H 0 Z",
+			width:       2,
+			join:        MiterJoin,
+			miterLimit:  4,
+			subpaths:    2,
+			bounds:      Rect{Point{-1, -1}, Point{11, 11}},
+		},
+		{
+			description: "arc",
+			rawPath:     "M 0 0 A 5 5 0 0 1 10 0",
+			width:       2,
+			join:        RoundJoin,
+			subpaths:    1,
+			bounds:      Rect{Point{-1, -6}, Point{11, 0}},
+		},
+		{
+			description: "smooth curves",
+			rawPath:     "M 10 10 C 50 -40 90 160 130 10 S 200 100 250 10",
+			width:       2,
+			join:        MiterJoin,
+			miterLimit:  4,
+			subpaths:    1,
+			bounds:      Rect{Point{9.219, -41.625}, Point{250.869, 67.855}},
+		},
+		{
+			description: "zero length with round cap",
+			rawPath:     "M 5 5 Z",
+			width:       2,
+			cap:         RoundCap,
+			subpaths:    1,
+			bounds:      Rect{Point{4, 4}, Point{6, 6}},
+		},
+		{
+			description: "zero length with butt cap",
+			rawPath:     "M 5 5 L 5 5",
+			width:       2,
+			cap:         ButtCap,
+		},
+		{
+			description: "zero width",
+			rawPath:     "M 0 0 H 10",
+			width:       0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			path, err := NewPath(test.rawPath)
+			if err != nil {
+				t.Fatalf("Stroke: unexpected error: %v", err)
+			}
+
+			outline := path.Stroke(test.width, test.join, test.cap, test.miterLimit)
+			subpaths := outline.Subpaths()
+
+			if test.subpaths > 0 && len(subpaths) != test.subpaths {
+				t.Errorf("Stroke: expected %d subpaths, actual %d",
+					test.subpaths, len(subpaths))
+			}
+
+			if test.bounds == (Rect{}) {
+				if len(subpaths) != 0 {
+					t.Errorf("Stroke: expected empty outline, actual %v", outline)
+				}
+				return
+			}
+
+			actual := outline.Bounds()
+			if !pointsWithin(actual.Min, test.bounds.Min, 0.05) ||
+				!pointsWithin(actual.Max, test.bounds.Max, 0.05) {
+				t.Errorf("Stroke: expected bounds %v, actual %v",
+					test.bounds, actual)
+			}
+
+			moves, closes := 0, 0
+			for _, command := range outline.Commands {
+				switch command.Symbol {
+				case "M":
+					moves++
+				case "Z":
+					closes++
+				}
+			}
+			if moves != closes {
+				t.Errorf("Stroke: expected closed subpaths, actual %v", outline)
+			}
+		})
+	}
+}
+
+func TestPathStrokeOutline(t *testing.T) {
+	path, err := NewPath("M 0 0 H 10")
+	if err != nil {
+		t.Fatalf("Stroke: unexpected error: %v", err)
+	}
+
+	expected := "M 0 -1 L 10 -1 L 10 1 L 0 1 Z"
+	actual := path.Stroke(2, MiterJoin, ButtCap, 4).String()
+	if actual != expected {
+		t.Errorf("Stroke: expected %s, actual %s", expected, actual)
+	}
+}
+
+func TestPathStrokeFillRules(t *testing.T) {
+	path, err := NewPath("M 0 0 A 50 50 0 1 1 100 0 L 100 100")
+	if err != nil {
+		t.Fatalf("Stroke: unexpected error: %v", err)
+	}
+
+	outline := path.Stroke(2, MiterJoin, ButtCap, 4)
+	if len(outline.Subpaths()) != 1 {
+		t.Errorf("Stroke: expected 1 subpath, actual %d", len(outline.Subpaths()))
+	}
+
+	for _, sample := range []struct {
+		point  Point
+		inside bool
+	}{
+		{Point{50, -50}, true},
+		{Point{50, -49.5}, true},
+		{Point{50, -51.5}, false},
+		{Point{50, -48}, false},
+		{Point{50, 0}, false},
+		{Point{100, 50}, true},
+		{Point{102, 50}, false},
+	} {
+		for _, rule := range []FillRule{NonZero, EvenOdd} {
+			actual := outline.Contains(sample.point.X, sample.point.Y, rule)
+			if actual != sample.inside {
+				t.Errorf("Stroke: expected %v inside to be %t, actual %t",
+					sample.point, sample.inside, actual)
+			}
+		}
+	}
+}
+
+func pointsWithin(a, b Point, tolerance float64) bool {
+	return math.Abs(a.X-b.X) <= tolerance && math.Abs(a.Y-b.Y) <= tolerance
+}
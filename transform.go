@@ -0,0 +1,285 @@
+package svg
+
+import (
+	"bytes"
+	"strconv"
+	"unicode"
+)
+
+// Transform is a single operation of a transform attribute, e.g. the
+// operation rotate(45 10 10) has the name "rotate" and three arguments.
+type Transform struct {
+	Name string
+	Args []float64
+}
+
+// transformArgs maps the name of a transform operation to the numbers of
+// arguments it accepts.
+var transformArgs = map[string][]int{
+	"matrix":    {6},
+	"translate": {1, 2},
+	"scale":     {1, 2},
+	"rotate":    {1, 3},
+	"skewX":     {1},
+	"skewY":     {1},
+}
+
+// Matrix returns the matrix of the operation. Returns the identity matrix for
+// an unknown operation.
+func (t Transform) Matrix() Matrix {
+	args := t.Args
+
+	switch {
+	case t.Name == "matrix" && len(args) == 6:
+		return Matrix{args[0], args[1], args[2], args[3], args[4], args[5]}
+	case t.Name == "translate" && len(args) == 1:
+		return Translate(args[0], 0)
+	case t.Name == "translate" && len(args) == 2:
+		return Translate(args[0], args[1])
+	case t.Name == "scale" && len(args) == 1:
+		return Scale(args[0], args[0])
+	case t.Name == "scale" && len(args) == 2:
+		return Scale(args[0], args[1])
+	case t.Name == "rotate" && len(args) == 1:
+		return Rotate(args[0])
+	case t.Name == "rotate" && len(args) == 3:
+		return Translate(args[1], args[2]).Multiply(Rotate(args[0])).
+			Multiply(Translate(-args[1], -args[2]))
+	case t.Name == "skewX" && len(args) == 1:
+		return SkewX(args[0])
+	case t.Name == "skewY" && len(args) == 1:
+		return SkewY(args[0])
+	}
+
+	return Identity
+}
+
+// ParseTransform parses the value of a transform attribute. It returns the
+// matrix of the whole transformation list together with its operations in the
+// order they are written.
+func ParseTransform(raw string) (Matrix, []Transform, error) {
+	parser := &valueParser{raw: raw}
+
+	transforms, err := parser.parse()
+	if err != nil {
+		return Identity, nil, err
+	}
+
+	matrix := Identity
+	for _, transform := range transforms {
+		matrix = matrix.Multiply(transform.Matrix())
+	}
+
+	return matrix, transforms, nil
+}
+
+// String returns the matrix as a value of a transform attribute in full
+// precision.
+func (m Matrix) String() string {
+	return m.Format(FormatOptions{})
+}
+
+// Format returns the matrix as a value of a transform attribute, written with
+// the precision, separator and leading zeros of opts. Translations and scales
+// are written in their short form. Returns an empty string for the identity
+// matrix.
+func (m Matrix) Format(opts FormatOptions) string {
+	separator := opts.Separator
+	if separator == "" {
+		separator = " "
+	}
+
+	var name string
+	var args []float64
+
+	switch {
+	case m.IsIdentity():
+		return ""
+	case m.A == 1 && m.B == 0 && m.C == 0 && m.D == 1 && m.F == 0:
+		name, args = "translate", []float64{m.E}
+	case m.A == 1 && m.B == 0 && m.C == 0 && m.D == 1:
+		name, args = "translate", []float64{m.E, m.F}
+	case m.B == 0 && m.C == 0 && m.E == 0 && m.F == 0 && m.A == m.D:
+		name, args = "scale", []float64{m.A}
+	case m.B == 0 && m.C == 0 && m.E == 0 && m.F == 0:
+		name, args = "scale", []float64{m.A, m.D}
+	default:
+		name, args = "matrix", []float64{m.A, m.B, m.C, m.D, m.E, m.F}
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(name)
+	buf.WriteByte('(')
+	for i, arg := range args {
+		if i > 0 {
+			buf.WriteString(separator)
+		}
+		buf.WriteString(formatNumber(arg, opts.places(), opts.TrimLeadingZeros))
+	}
+	buf.WriteByte(')')
+
+	return buf.String()
+}
+
+// valueParser reads attribute values made of numbers, such as transforms.
+type valueParser struct {
+	raw string
+	pos int
+}
+
+// parse reads all operations of the transform attribute.
+func (p *valueParser) parse() ([]Transform, error) {
+	var transforms []Transform
+
+	p.skipSpace()
+	for p.pos < len(p.raw) {
+		transform, err := p.transform()
+		if err != nil {
+			return nil, err
+		}
+		transforms = append(transforms, transform)
+
+		p.skipSpace()
+		if p.peek() == ',' {
+			p.pos++
+			p.skipSpace()
+
+			if p.pos == len(p.raw) {
+				return nil, p.errorf(p.pos, "Unexpected end of transform")
+			}
+		}
+	}
+
+	return transforms, nil
+}
+
+// transform reads a single operation.
+func (p *valueParser) transform() (Transform, error) {
+	start := p.pos
+	for p.pos < len(p.raw) && isLetter(p.raw[p.pos]) {
+		p.pos++
+	}
+
+	name := p.raw[start:p.pos]
+	if name == "" {
+		return Transform{}, p.unexpected()
+	}
+
+	counts, ok := transformArgs[name]
+	if !ok {
+		return Transform{}, p.errorf(start, "Invalid transform '%s'", name)
+	}
+
+	p.skipSpace()
+	if p.peek() != '(' {
+		return Transform{}, p.unexpected()
+	}
+	p.pos++
+
+	args := []float64{}
+	p.skipSpace()
+	for p.peek() != ')' {
+		if len(args) > 0 && p.peek() == ',' {
+			p.pos++
+			p.skipSpace()
+		}
+
+		number, err := p.number()
+		if err != nil {
+			return Transform{}, err
+		}
+		args = append(args, number)
+		p.skipSpace()
+	}
+	p.pos++
+
+	for _, count := range counts {
+		if len(args) == count {
+			return Transform{Name: name, Args: args}, nil
+		}
+	}
+
+	return Transform{}, p.errorf(start,
+		"Incorrect number of parameters for %s", name)
+}
+
+// number reads a number, optionally signed and in scientific notation.
+func (p *valueParser) number() (float64, error) {
+	start := p.pos
+
+	if c := p.peek(); c == '+' || c == '-' {
+		p.pos++
+	}
+
+	digits := p.digits()
+	if p.peek() == '.' {
+		p.pos++
+		digits += p.digits()
+	}
+
+	if digits == 0 {
+		p.pos = start
+		return 0, p.unexpected()
+	}
+
+	if c := p.peek(); c == 'e' || c == 'E' {
+		exponent := p.pos
+		p.pos++
+		if c := p.peek(); c == '+' || c == '-' {
+			p.pos++
+		}
+		if p.digits() == 0 {
+			p.pos = exponent
+		}
+	}
+
+	number, err := strconv.ParseFloat(p.raw[start:p.pos], 64)
+	if err != nil {
+		return 0, p.errorf(start, "Invalid parameter syntax")
+	}
+
+	return number, nil
+}
+
+// digits skips decimal digits and returns how many were skipped.
+func (p *valueParser) digits() int {
+	start := p.pos
+	for p.pos < len(p.raw) && p.raw[p.pos] >= '0' && p.raw[p.pos] <= '9' {
+		p.pos++
+	}
+	return p.pos - start
+}
+
+// skipSpace moves past any whitespace.
+func (p *valueParser) skipSpace() {
+	for p.pos < len(p.raw) && unicode.IsSpace(rune(p.raw[p.pos])) {
+		p.pos++
+	}
+}
+
+// peek returns the current character or zero at the end of the input.
+func (p *valueParser) peek() byte {
+	if p.pos < len(p.raw) {
+		return p.raw[p.pos]
+	}
+	return 0
+}
+
+// unexpected returns an error about the current character.
+func (p *valueParser) unexpected() error {
+	if p.pos == len(p.raw) {
+		return p.errorf(p.pos, "Unexpected end of transform")
+	}
+	return p.errorf(p.pos, "Unexpected symbol '%c'", p.raw[p.pos])
+}
+
+// errorf returns a SyntaxError that occurred at the given offset.
+func (p *valueParser) errorf(offset int, format string,
+	args ...interface{}) error {
+
+	return syntaxError(p.raw, offset, format, args...)
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
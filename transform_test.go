@@ -0,0 +1,217 @@
+package svg_test
+
+import (
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+func TestParseTransform(t *testing.T) {
+	tests := []struct {
+		description        string
+		raw                string
+		expectedMatrix     Matrix
+		expectedTransforms []Transform
+	}{
+		{
+			description:    "empty",
+			raw:            " ",
+			expectedMatrix: Identity,
+		},
+		{
+			description:    "matrix",
+			raw:            "matrix(1,2,3,4,5,6)",
+			expectedMatrix: Matrix{1, 2, 3, 4, 5, 6},
+			expectedTransforms: []Transform{
+				{Name: "matrix", Args: []float64{1, 2, 3, 4, 5, 6}},
+			},
+		},
+		{
+			description:    "translate with one argument",
+			raw:            "translate(10)",
+			expectedMatrix: Translate(10, 0),
+			expectedTransforms: []Transform{
+				{Name: "translate", Args: []float64{10}},
+			},
+		},
+		{
+			description:    "uniform scale",
+			raw:            "scale(2)",
+			expectedMatrix: Scale(2, 2),
+			expectedTransforms: []Transform{
+				{Name: "scale", Args: []float64{2}},
+			},
+		},
+		{
+			description:    "rotate about center",
+			raw:            "rotate(90 10 10)",
+			expectedMatrix: Matrix{0, 1, -1, 0, 20, 0},
+			expectedTransforms: []Transform{
+				{Name: "rotate", Args: []float64{90, 10, 10}},
+			},
+		},
+		{
+			description:    "skews",
+			raw:            "skewX(45) skewY(45)",
+			expectedMatrix: SkewX(45).Multiply(SkewY(45)),
+			expectedTransforms: []Transform{
+				{Name: "skewX", Args: []float64{45}},
+				{Name: "skewY", Args: []float64{45}},
+			},
+		},
+		{
+			description:    "list with commas and whitespace",
+			raw:            "\ttranslate ( 10 , -5e1 ) ,scale(.5-1.5E0),rotate(30)\n",
+			expectedMatrix: Translate(10, -50).Multiply(Scale(0.5, -1.5)).Multiply(Rotate(30)),
+			expectedTransforms: []Transform{
+				{Name: "translate", Args: []float64{10, -50}},
+				{Name: "scale", Args: []float64{0.5, -1.5}},
+				{Name: "rotate", Args: []float64{30}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			matrix, transforms, err := ParseTransform(test.raw)
+			if err != nil {
+				t.Fatalf("Transform: unexpected error: %v", err)
+			}
+
+			if !matricesClose(matrix, test.expectedMatrix) {
+				t.Errorf("Transform: expected %v, actual %v",
+					test.expectedMatrix, matrix)
+			}
+
+			if len(transforms) != len(test.expectedTransforms) {
+				t.Fatalf("Transform: expected %v, actual %v",
+					test.expectedTransforms, transforms)
+			}
+
+			for i, expected := range test.expectedTransforms {
+				actual := transforms[i]
+				if actual.Name != expected.Name ||
+					len(actual.Args) != len(expected.Args) {
+					t.Fatalf("Transform: expected %v, actual %v", expected, actual)
+				}
+
+				for j, arg := range expected.Args {
+					if !floatsClose(arg, actual.Args[j]) {
+						t.Errorf("Transform: expected %v, actual %v",
+							expected, actual)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseTransformErrors(t *testing.T) {
+	tests := []struct {
+		description   string
+		raw           string
+		expectedError string
+	}{
+		{
+			description:   "unknown transform",
+			raw:           "translate(1) shear(2)",
+			expectedError: "Invalid transform 'shear' at line 1, column 14",
+		},
+		{
+			description:   "missing parenthesis",
+			raw:           "scale 2",
+			expectedError: "Unexpected symbol '2' at line 1, column 7",
+		},
+		{
+			description:   "incorrect number of parameters",
+			raw:           "rotate(1, 2)",
+			expectedError: "Incorrect number of parameters for rotate at line 1, column 1",
+		},
+		{
+			description:   "invalid number",
+			raw:           "translate(1, -)",
+			expectedError: "Unexpected symbol '-' at line 1, column 14",
+		},
+		{
+			description:   "unterminated",
+			raw:           "translate(1",
+			expectedError: "Unexpected end of transform at line 1, column 12",
+		},
+		{
+			description:   "trailing comma",
+			raw:           "translate(1),",
+			expectedError: "Unexpected end of transform at line 1, column 14",
+		},
+		{
+			description:   "unexpected symbol",
+			raw:           "translate(1);",
+			expectedError: "Unexpected symbol ';' at line 1, column 13",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			_, transforms, err := ParseTransform(test.raw)
+			if transforms != nil {
+				t.Fatalf("Transform: expected nil, actual %v", transforms)
+			}
+
+			if err == nil || err.Error() != test.expectedError {
+				t.Fatalf("Transform: expected %v, actual %v",
+					test.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestMatrixString(t *testing.T) {
+	tests := []struct {
+		description string
+		matrix      Matrix
+		expected    string
+	}{
+		{"identity", Identity, ""},
+		{"horizontal translation", Translate(10, 0), "translate(10)"},
+		{"translation", Translate(10, -2.5), "translate(10 -2.5)"},
+		{"uniform scale", Scale(2, 2), "scale(2)"},
+		{"scale", Scale(2, 0.5), "scale(2 0.5)"},
+		{"matrix", Matrix{1, 2, 3, 4, 5, 6}, "matrix(1 2 3 4 5 6)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			if actual := test.matrix.String(); actual != test.expected {
+				t.Errorf("Matrix: expected %q, actual %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMatrixFormatRoundTrip(t *testing.T) {
+	matrix := Translate(10, 20).Multiply(Rotate(30)).Multiply(SkewX(10))
+
+	formatted := matrix.Format(FormatOptions{Precision: Decimals(9), Separator: ","})
+	actual, _, err := ParseTransform(formatted)
+	if err != nil {
+		t.Fatalf("Matrix: unexpected error for %q: %v", formatted, err)
+	}
+
+	if !matricesClose(actual, matrix) {
+		t.Errorf("Matrix: expected %v, actual %v", matrix, actual)
+	}
+}
+
+func TestMatrixFormatDefault(t *testing.T) {
+	matrix := Matrix{A: 0.5, B: 0.25, C: -0.125, D: 1.75, E: 10.5, F: -3.25}
+
+	expected := "matrix(0.5 0.25 -0.125 1.75 10.5 -3.25)"
+	if actual := matrix.Format(FormatOptions{}); actual != expected {
+		t.Errorf("Matrix: expected %q, actual %q", expected, actual)
+	}
+}
+
+func matricesClose(a, b Matrix) bool {
+	return floatsClose(a.A, b.A) && floatsClose(a.B, b.B) &&
+		floatsClose(a.C, b.C) && floatsClose(a.D, b.D) &&
+		floatsClose(a.E, b.E) && floatsClose(a.F, b.F)
+}
@@ -0,0 +1,306 @@
+package svg
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// ErrSkipChildren can be returned by the function passed to Walk to skip the
+// descendants of the element it is called for.
+var ErrSkipChildren = errors.New("skip children")
+
+// ErrSkipAll can be returned by the function passed to Walk to stop the walk.
+var ErrSkipAll = errors.New("skip all")
+
+// Walk calls fn for the element and all of its descendants in document order.
+// The walk stops at the first error returned by fn, which is returned by
+// Walk, except for ErrSkipChildren and ErrSkipAll.
+func (e *Element) Walk(fn func(element *Element) error) error {
+	if err := walk(e, fn); err != ErrSkipAll {
+		return err
+	}
+	return nil
+}
+
+func walk(e *Element, fn func(*Element) error) error {
+	switch err := fn(e); err {
+	case nil:
+	case ErrSkipChildren:
+		return nil
+	default:
+		return err
+	}
+
+	for _, child := range e.Children {
+		if err := walk(child, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Descendants returns an iterator over the descendants of the element in
+// document order.
+func (e *Element) Descendants() iter.Seq[*Element] {
+	return func(yield func(*Element) bool) {
+		descendants(e, yield)
+	}
+}
+
+func descendants(e *Element, yield func(*Element) bool) bool {
+	for _, child := range e.Children {
+		if !yield(child) || !descendants(child, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// Ancestors returns an iterator over the ancestors of the element, starting
+// from its parent.
+func (e *Element) Ancestors() iter.Seq[*Element] {
+	return func(yield func(*Element) bool) {
+		for parent := e.Parent(); parent != nil; parent = parent.Parent() {
+			if !yield(parent) {
+				return
+			}
+		}
+	}
+}
+
+// Parent returns the element that contains the element, or nil for a root.
+// Elements decoded by New and elements added with the methods of Element
+// know their parent. An element that is added to or removed from Children
+// directly does not, until it is moved with one of the methods.
+func (e *Element) Parent() *Element {
+	if e.parent == nil {
+		return nil
+	}
+
+	for _, child := range e.parent.Children {
+		if child == e {
+			return e.parent
+		}
+	}
+
+	return nil
+}
+
+// IndexOf returns the position of the node in the content of the element, as
+// held in Nodes after any change made with the methods of Element, or -1 if
+// the element does not contain it.
+func (e *Element) IndexOf(node Node) int {
+	for i, n := range content(e) {
+		if n == node {
+			return i
+		}
+	}
+	return -1
+}
+
+// Insert inserts the nodes into the content of the element at the index.
+// Elements that are already part of a tree are moved. Nodes, Children and
+// Content are kept consistent with each other.
+func (e *Element) Insert(index int, nodes ...Node) error {
+	if index < 0 || index > len(content(e)) {
+		return fmt.Errorf("Index %d out of range for <%s>", index, e.Name)
+	}
+	return e.splice(index, 0, nodes)
+}
+
+// Append adds the nodes to the end of the content of the element. Elements
+// that are already part of a tree are moved.
+func (e *Element) Append(nodes ...Node) error {
+	return e.splice(len(content(e)), 0, nodes)
+}
+
+// Remove removes the node from the content of the element. Returns false if
+// the element does not contain it.
+func (e *Element) Remove(node Node) bool {
+	index := e.IndexOf(node)
+	if index < 0 {
+		return false
+	}
+
+	e.splice(index, 1, nil)
+	return true
+}
+
+// Replace replaces the node in the content of the element with the nodes.
+// Elements that are already part of a tree are moved.
+func (e *Element) Replace(old Node, nodes ...Node) error {
+	index := e.IndexOf(old)
+	if index < 0 {
+		return fmt.Errorf("Node is not a child of <%s>", e.Name)
+	}
+	return e.splice(index, 1, nodes)
+}
+
+// Wrap replaces the element in its parent with the wrapper and moves the
+// element to the end of the content of the wrapper.
+func (e *Element) Wrap(wrapper *Element) error {
+	parent := e.Parent()
+	if parent == nil {
+		return fmt.Errorf("Element <%s> has no parent", e.Name)
+	}
+
+	if wrapper == e {
+		return fmt.Errorf("Element <%s> cannot wrap itself", e.Name)
+	}
+
+	if err := parent.Replace(e, wrapper); err != nil {
+		return err
+	}
+	return wrapper.Append(e)
+}
+
+// Unwrap replaces the element in its parent with its content.
+func (e *Element) Unwrap() error {
+	parent := e.Parent()
+	if parent == nil {
+		return fmt.Errorf("Element <%s> has no parent", e.Name)
+	}
+
+	return parent.Replace(e, content(e)...)
+}
+
+// splice replaces count nodes of the content of the element, starting at the
+// index, with the nodes.
+func (e *Element) splice(index, count int, nodes []Node) error {
+	inserted := map[*Element]bool{}
+
+	for _, node := range nodes {
+		child, ok := node.(*Element)
+		if !ok {
+			continue
+		}
+
+		if inserted[child] {
+			return fmt.Errorf("Element <%s> is inserted twice", child.Name)
+		}
+
+		for ancestor := e; ancestor != nil; ancestor = ancestor.Parent() {
+			if ancestor == child {
+				return fmt.Errorf("Element <%s> cannot be inserted into itself",
+					child.Name)
+			}
+		}
+
+		inserted[child] = true
+	}
+
+	for _, node := range nodes {
+		child, ok := node.(*Element)
+		if !ok {
+			continue
+		}
+
+		if parent := child.Parent(); parent != nil && parent != e {
+			parent.Remove(child)
+		}
+	}
+
+	current := content(e)
+	result := []Node{}
+
+	for i, node := range current {
+		if i == index {
+			result = append(result, nodes...)
+		}
+
+		child, isElement := node.(*Element)
+		if i >= index && i < index+count {
+			if isElement && !inserted[child] {
+				child.parent = nil
+			}
+			continue
+		}
+
+		if !isElement || !inserted[child] {
+			result = append(result, node)
+		}
+	}
+
+	if index == len(current) {
+		result = append(result, nodes...)
+	}
+
+	e.setNodes(result)
+	return nil
+}
+
+// setNodes sets the content of the element and updates its children, text
+// and the parents of the children to match.
+func (e *Element) setNodes(nodes []Node) {
+	e.Nodes = nodes
+	e.Children = nil
+
+	for _, node := range nodes {
+		if child, ok := node.(*Element); ok {
+			child.parent = e
+			e.Children = append(e.Children, child)
+		}
+	}
+
+	e.Content = textContent(nodes)
+}
+
+// Clone returns a deep copy of the element without a parent.
+func (e *Element) Clone() *Element {
+	clone := &Element{
+		Name:    e.Name,
+		Content: e.Content,
+	}
+
+	if e.Attributes != nil {
+		clone.Attributes = make(map[string]string, len(e.Attributes))
+		for name, value := range e.Attributes {
+			clone.Attributes[name] = value
+		}
+	}
+
+	if e.Source != nil {
+		source := *e.Source
+		clone.Source = &source
+	}
+
+	if e.AttributeOrder != nil {
+		clone.AttributeOrder = append([]string{}, e.AttributeOrder...)
+	}
+
+	clones := map[*Element]*Element{}
+
+	if e.Children != nil {
+		clone.Children = make([]*Element, len(e.Children))
+		for i, child := range e.Children {
+			clone.Children[i] = child.Clone()
+			clone.Children[i].parent = clone
+			clones[child] = clone.Children[i]
+		}
+	}
+
+	if e.Nodes != nil {
+		clone.Nodes = make([]Node, len(e.Nodes))
+		for i, node := range e.Nodes {
+			if child, ok := node.(*Element); ok {
+				if childClone, ok := clones[child]; ok {
+					node = childClone
+				} else {
+					node = child.Clone()
+				}
+			}
+			clone.Nodes[i] = node
+		}
+	}
+
+	if e.Prolog != nil {
+		clone.Prolog = append([]Node{}, e.Prolog...)
+	}
+	if e.Epilog != nil {
+		clone.Epilog = append([]Node{}, e.Epilog...)
+	}
+
+	return clone
+}
@@ -0,0 +1,333 @@
+package svg_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+func parse(t *testing.T, raw string) *Element {
+	element, err := New(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("New: unexpected error: %s", err)
+	}
+	return element
+}
+
+func names(elements []*Element) string {
+	var result []string
+	for _, element := range elements {
+		result = append(result, element.Name)
+	}
+	return strings.Join(result, " ")
+}
+
+func TestElementWalk(t *testing.T) {
+	root := parse(t, `<svg><g><rect/><circle/></g><defs><path/></defs><line/></svg>`)
+
+	tests := []struct {
+		description string
+		fn          func(*Element) error
+		expected    string
+		expectedErr error
+	}{
+		{
+			description: "all elements",
+			fn:          func(*Element) error { return nil },
+			expected:    "svg g rect circle defs path line",
+		},
+		{
+			description: "skip children",
+			fn: func(e *Element) error {
+				if e.Name == "g" {
+					return ErrSkipChildren
+				}
+				return nil
+			},
+			expected: "svg g defs path line",
+		},
+		{
+			description: "skip all",
+			fn: func(e *Element) error {
+				if e.Name == "circle" {
+					return ErrSkipAll
+				}
+				return nil
+			},
+			expected: "svg g rect circle",
+		},
+		{
+			description: "error",
+			fn: func(e *Element) error {
+				if e.Name == "defs" {
+					return errors.New("defs")
+				}
+				return nil
+			},
+			expected:    "svg g rect circle defs",
+			expectedErr: errors.New("defs"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var visited []*Element
+			err := root.Walk(func(e *Element) error {
+				visited = append(visited, e)
+				return test.fn(e)
+			})
+
+			if actual := names(visited); actual != test.expected {
+				t.Fatalf("Walk: expected %s, actual %s", test.expected, actual)
+			}
+
+			if (err == nil) != (test.expectedErr == nil) ||
+				err != nil && err.Error() != test.expectedErr.Error() {
+				t.Fatalf("Walk: expected error %v, actual %v", test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestElementDescendantsAndAncestors(t *testing.T) {
+	root := parse(t, `<svg><g><rect/><circle/></g><line/></svg>`)
+
+	var descendants []*Element
+	for element := range root.Descendants() {
+		descendants = append(descendants, element)
+		if element.Name == "circle" {
+			break
+		}
+	}
+
+	if actual := names(descendants); actual != "g rect circle" {
+		t.Fatalf("Descendants: expected g rect circle, actual %s", actual)
+	}
+
+	var ancestors []*Element
+	for element := range descendants[2].Ancestors() {
+		ancestors = append(ancestors, element)
+	}
+
+	if actual := names(ancestors); actual != "g svg" {
+		t.Fatalf("Ancestors: expected g svg, actual %s", actual)
+	}
+}
+
+func TestElementParent(t *testing.T) {
+	root := parse(t, `<svg><g><rect/></g></svg>`)
+	g := root.Children[0]
+	rect := g.Children[0]
+
+	if root.Parent() != nil || g.Parent() != root || rect.Parent() != g {
+		t.Fatalf("Parent: unexpected parents of decoded elements")
+	}
+
+	g.Children = nil
+	if rect.Parent() != nil {
+		t.Fatalf("Parent: expected nil for removed element, actual %v", rect.Parent())
+	}
+}
+
+func TestElementMutations(t *testing.T) {
+	tests := []struct {
+		description string
+		modify      func(root *Element) error
+		expected    string
+	}{
+		{
+			description: "insert",
+			modify: func(root *Element) error {
+				return root.Insert(1, &Element{Name: "path"}, Text{Data: "t"})
+			},
+			expected: `<svg><g>a<rect/>b</g><path/>t<circle/></svg>`,
+		},
+		{
+			description: "insert moves an element",
+			modify: func(root *Element) error {
+				return root.Insert(0, root.Children[0].Children[0])
+			},
+			expected: `<svg><rect/><g>ab</g><circle/></svg>`,
+		},
+		{
+			description: "append",
+			modify: func(root *Element) error {
+				return root.Append(Comment{Data: "c"}, root.Children[0])
+			},
+			expected: `<svg><circle/><!--c--><g>a<rect/>b</g></svg>`,
+		},
+		{
+			description: "remove",
+			modify: func(root *Element) error {
+				g := root.Children[0]
+				if !g.Remove(Text{Data: "a"}) || !g.Remove(g.Children[0]) {
+					return errors.New("not removed")
+				}
+				return nil
+			},
+			expected: `<svg><g>b</g><circle/></svg>`,
+		},
+		{
+			description: "replace",
+			modify: func(root *Element) error {
+				return root.Replace(root.Children[1],
+					&Element{Name: "ellipse"}, &Element{Name: "line"})
+			},
+			expected: `<svg><g>a<rect/>b</g><ellipse/><line/></svg>`,
+		},
+		{
+			description: "wrap",
+			modify: func(root *Element) error {
+				return root.Children[1].Wrap(&Element{
+					Name:       "a",
+					Attributes: map[string]string{"href": "#"},
+				})
+			},
+			expected: `<svg><g>a<rect/>b</g><a href="#"><circle/></a></svg>`,
+		},
+		{
+			description: "unwrap",
+			modify: func(root *Element) error {
+				return root.Children[0].Unwrap()
+			},
+			expected: `<svg>a<rect/>b<circle/></svg>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			root := parse(t, `<svg><g>a<rect/>b</g><circle/></svg>`)
+			if err := test.modify(root); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			buf := &bytes.Buffer{}
+			if err := root.RenderWithOptions(buf, RenderOptions{SelfClose: true}); err != nil {
+				t.Fatalf("Render: unexpected error: %s", err)
+			}
+
+			if actual := buf.String(); actual != test.expected {
+				t.Fatalf("Render: expected %s, actual %s", test.expected, actual)
+			}
+
+			for element := range root.Descendants() {
+				if parent := element.Parent(); parent == nil {
+					t.Fatalf("Parent: expected parent of <%s>", element.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestElementMutationsContent(t *testing.T) {
+	root := parse(t, `<text>a<tspan/>b</text>`)
+
+	if root.Content != "ab" {
+		t.Fatalf("Content: expected ab, actual %s", root.Content)
+	}
+
+	if err := root.Append(Text{Data: "c"}); err != nil {
+		t.Fatalf("Append: unexpected error: %s", err)
+	}
+
+	if root.Content != "abc" {
+		t.Fatalf("Content: expected abc, actual %s", root.Content)
+	}
+}
+
+func TestElementMutationsErrors(t *testing.T) {
+	tests := []struct {
+		description string
+		modify      func(root *Element) error
+		expected    string
+	}{
+		{
+			description: "index out of range",
+			modify: func(root *Element) error {
+				return root.Insert(3, &Element{Name: "path"})
+			},
+			expected: "Index 3 out of range for <svg>",
+		},
+		{
+			description: "insert into itself",
+			modify: func(root *Element) error {
+				return root.Children[0].Append(root)
+			},
+			expected: "Element <svg> cannot be inserted into itself",
+		},
+		{
+			description: "insert twice",
+			modify: func(root *Element) error {
+				path := &Element{Name: "path"}
+				return root.Append(path, path)
+			},
+			expected: "Element <path> is inserted twice",
+		},
+		{
+			description: "replace missing node",
+			modify: func(root *Element) error {
+				return root.Replace(&Element{Name: "path"})
+			},
+			expected: "Node is not a child of <svg>",
+		},
+		{
+			description: "wrap root",
+			modify: func(root *Element) error {
+				return root.Wrap(&Element{Name: "g"})
+			},
+			expected: "Element <svg> has no parent",
+		},
+		{
+			description: "unwrap root",
+			modify: func(root *Element) error {
+				return root.Unwrap()
+			},
+			expected: "Element <svg> has no parent",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			root := parse(t, `<svg><g>a<rect/>b</g><circle/></svg>`)
+
+			err := test.modify(root)
+			if err == nil {
+				t.Fatalf("expected error, actual nil")
+			}
+
+			if err.Error() != test.expected {
+				t.Fatalf("expected error %s, actual %s", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestElementClone(t *testing.T) {
+	root := parse(t, `<!-- c --><svg width="1"><g>a<rect x="1"/>b</g></svg>`)
+	clone := root.Clone()
+
+	if !clone.Equal(root) {
+		t.Fatalf("Clone: expected %v, actual %v", root, clone)
+	}
+
+	clone.Attributes["width"] = "2"
+	clone.Children[0].Children[0].Attributes["x"] = "2"
+	clone.Children[0].Remove(Text{Data: "a"})
+
+	expected := `<!-- c --><svg width="1"><g>a<rect x="1"></rect>b</g></svg>`
+	if actual := render(t, root); actual != expected {
+		t.Fatalf("Clone: expected original %s, actual %s", expected, actual)
+	}
+
+	expected = `<!-- c --><svg width="2"><g><rect x="2"></rect>b</g></svg>`
+	if actual := render(t, clone); actual != expected {
+		t.Fatalf("Clone: expected %s, actual %s", expected, actual)
+	}
+
+	if clone.Parent() != nil || clone.Children[0].Parent() != clone {
+		t.Fatalf("Clone: unexpected parents")
+	}
+}
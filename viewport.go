@@ -0,0 +1,217 @@
+package svg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WalkTransforms calls fn for the element and all of its descendants in
+// document order, together with the current transformation matrix of each.
+// The matrix maps the user coordinate system of the element, the one its
+// geometry attributes are given in, to the viewport of the element the walk
+// starts from. It includes the transform attributes of the element and its
+// ancestors and the viewBox mapping of the svg elements that contain it.
+// The walk stops at the first error, either returned by fn or from parsing an
+// attribute.
+func (e *Element) WalkTransforms(fn func(element *Element, ctm Matrix) error) error {
+	return walkTransforms(e, Identity, true, fn)
+}
+
+func walkTransforms(e *Element, ctm Matrix, root bool,
+	fn func(*Element, Matrix) error) error {
+
+	if raw, ok := e.Attributes["transform"]; ok {
+		matrix, _, err := ParseTransform(raw)
+		if err != nil {
+			return fmt.Errorf("Invalid transform of %s: %w", e.Name, err)
+		}
+		ctm = ctm.Multiply(matrix)
+	}
+
+	if err := fn(e, ctm); err != nil {
+		return err
+	}
+
+	if e.Name == "svg" {
+		viewport, err := viewportTransform(e, root)
+		if err != nil {
+			return err
+		}
+		ctm = ctm.Multiply(viewport)
+	}
+
+	for _, child := range e.Children {
+		if err := walkTransforms(child, ctm, false, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// viewportTransform returns the matrix that maps the coordinate system
+// established by an svg element to the one it is placed in. The position of
+// the outermost svg element is ignored. A width or height that is missing or
+// relative falls back to the size of the viewBox.
+func viewportTransform(e *Element, root bool) (Matrix, error) {
+	var x, y float64
+	if !root {
+		x, _ = parseLength(e.Attributes["x"])
+		y, _ = parseLength(e.Attributes["y"])
+	}
+
+	raw, ok := e.Attributes["viewBox"]
+	if !ok {
+		return Translate(x, y), nil
+	}
+
+	viewBox, err := ParseViewBox(raw)
+	if err != nil {
+		return Identity, err
+	}
+
+	width, err := parseLength(e.Attributes["width"])
+	if err != nil || width == 0 {
+		width = viewBox.Width()
+	}
+
+	height, err := parseLength(e.Attributes["height"])
+	if err != nil || height == 0 {
+		height = viewBox.Height()
+	}
+
+	matrix, err := ViewBoxTransform(viewBox,
+		e.Attributes["preserveAspectRatio"], width, height)
+	if err != nil {
+		return Identity, err
+	}
+
+	return Translate(x, y).Multiply(matrix), nil
+}
+
+// ParseViewBox parses the value of a viewBox attribute.
+func ParseViewBox(raw string) (Rect, error) {
+	values, err := parseNumbers(raw)
+	if err != nil || len(values) != 4 {
+		return Rect{}, fmt.Errorf("Invalid viewBox '%s'", raw)
+	}
+
+	if values[2] <= 0 || values[3] <= 0 {
+		return Rect{}, fmt.Errorf("Invalid viewBox size '%s'", raw)
+	}
+
+	return Rect{
+		Min: Point{values[0], values[1]},
+		Max: Point{values[0] + values[2], values[1] + values[3]},
+	}, nil
+}
+
+// ViewBoxTransform returns the matrix that maps the viewBox to a viewport of
+// the given size, aligned and scaled according to the value of a
+// preserveAspectRatio attribute. An empty value means "xMidYMid meet".
+func ViewBoxTransform(viewBox Rect, preserveAspectRatio string,
+	width, height float64) (Matrix, error) {
+
+	align, slice, err := parseAspectRatio(preserveAspectRatio)
+	if err != nil {
+		return Identity, err
+	}
+
+	sx, sy := width/viewBox.Width(), height/viewBox.Height()
+
+	if align == "none" {
+		return Scale(sx, sy).Multiply(
+			Translate(-viewBox.Min.X, -viewBox.Min.Y)), nil
+	}
+
+	scale := sx
+	if (slice && sy > sx) || (!slice && sy < sx) {
+		scale = sy
+	}
+
+	tx := -viewBox.Min.X * scale
+	ty := -viewBox.Min.Y * scale
+
+	switch align[:4] {
+	case "xMid":
+		tx += (width - viewBox.Width()*scale) / 2
+	case "xMax":
+		tx += width - viewBox.Width()*scale
+	}
+
+	switch align[4:] {
+	case "YMid":
+		ty += (height - viewBox.Height()*scale) / 2
+	case "YMax":
+		ty += height - viewBox.Height()*scale
+	}
+
+	return Matrix{A: scale, D: scale, E: tx, F: ty}, nil
+}
+
+// aspectRatioAlignments are the valid alignments of preserveAspectRatio.
+var aspectRatioAlignments = map[string]bool{
+	"none":     true,
+	"xMinYMin": true, "xMidYMin": true, "xMaxYMin": true,
+	"xMinYMid": true, "xMidYMid": true, "xMaxYMid": true,
+	"xMinYMax": true, "xMidYMax": true, "xMaxYMax": true,
+}
+
+// parseAspectRatio parses the value of a preserveAspectRatio attribute into
+// its alignment and whether the viewBox should cover the whole viewport.
+func parseAspectRatio(raw string) (string, bool, error) {
+	fields := strings.Fields(raw)
+	if len(fields) > 0 && fields[0] == "defer" {
+		fields = fields[1:]
+	}
+
+	if len(fields) == 0 {
+		return "xMidYMid", false, nil
+	}
+
+	if !aspectRatioAlignments[fields[0]] || len(fields) > 2 {
+		return "", false, fmt.Errorf("Invalid preserveAspectRatio '%s'", raw)
+	}
+
+	if len(fields) == 1 {
+		return fields[0], false, nil
+	}
+
+	switch fields[1] {
+	case "meet":
+		return fields[0], false, nil
+	case "slice":
+		return fields[0], true, nil
+	}
+
+	return "", false, fmt.Errorf("Invalid preserveAspectRatio '%s'", raw)
+}
+
+// lengthUnits maps the absolute length units to their size in user units.
+var lengthUnits = map[string]float64{
+	"":   1,
+	"px": 1,
+	"pt": 4.0 / 3,
+	"pc": 16,
+	"mm": 96 / 25.4,
+	"cm": 96 / 2.54,
+	"in": 96,
+}
+
+// parseLength parses an absolute length into user units. Relative lengths,
+// such as percentages, are not supported.
+func parseLength(raw string) (float64, error) {
+	parser := &valueParser{raw: strings.TrimSpace(raw)}
+
+	value, err := parser.number()
+	if err != nil {
+		return 0, fmt.Errorf("Invalid length '%s'", raw)
+	}
+
+	factor, ok := lengthUnits[parser.raw[parser.pos:]]
+	if !ok {
+		return 0, fmt.Errorf("Unsupported length '%s'", raw)
+	}
+
+	return value * factor, nil
+}
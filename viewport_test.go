@@ -0,0 +1,167 @@
+package svg_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/catiepg/svg"
+)
+
+func TestElementWalkTransforms(t *testing.T) {
+	raw := `
+	<svg width="200" height="100" viewBox="0 0 100 100">
+		<g transform="translate(10 20)">
+			<path id="a" d="M 0 0" />
+			<g transform="scale(2)">
+				<path id="b" d="M 0 0" transform="rotate(90)" />
+			</g>
+		</g>
+		<svg x="10" y="5" width="50" height="50" viewBox="-10 -10 20 20">
+			<circle id="c" r="10" />
+		</svg>
+	</svg>
+	`
+
+	root, err := New(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("New: unexpected error: %s", err)
+	}
+
+	// The root viewBox is scaled by 1 to fit the height and centered.
+	viewport := Translate(50, 0)
+	expected := map[string]Matrix{
+		"a": viewport.Multiply(Translate(10, 20)),
+		"b": viewport.Multiply(Translate(10, 20)).Multiply(Scale(2, 2)).
+			Multiply(Rotate(90)),
+		"c": viewport.Multiply(Translate(10, 5)).Multiply(Scale(2.5, 2.5)).
+			Multiply(Translate(10, 10)),
+	}
+
+	visited := 0
+	err = root.WalkTransforms(func(element *Element, ctm Matrix) error {
+		visited++
+
+		if element == root && !ctm.IsIdentity() {
+			t.Errorf("Element: expected identity for root, actual %v", ctm)
+		}
+
+		id, ok := element.Attributes["id"]
+		if !ok {
+			return nil
+		}
+
+		if !matricesClose(ctm, expected[id]) {
+			t.Errorf("Element: expected %v for %s, actual %v", expected[id], id, ctm)
+		}
+		delete(expected, id)
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Element: unexpected error: %s", err)
+	}
+
+	if visited != 7 || len(expected) != 0 {
+		t.Errorf("Element: expected all elements to be visited, missing %v",
+			expected)
+	}
+}
+
+func TestElementWalkTransformsErrors(t *testing.T) {
+	stop := errors.New("stop")
+
+	root := &Element{
+		Name: "svg",
+		Children: []*Element{
+			{Name: "g", Attributes: map[string]string{"transform": "scale(1"}},
+		},
+	}
+
+	visited := 0
+	err := root.WalkTransforms(func(*Element, Matrix) error {
+		visited++
+		return nil
+	})
+	if err == nil || !strings.HasPrefix(err.Error(), "Invalid transform of g") {
+		t.Errorf("Element: expected transform error, actual %v", err)
+	}
+
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("Element: expected a SyntaxError, actual %v", err)
+	}
+
+	err = root.WalkTransforms(func(*Element, Matrix) error {
+		return stop
+	})
+	if err != stop {
+		t.Errorf("Element: expected %v, actual %v", stop, err)
+	}
+}
+
+func TestViewBoxTransform(t *testing.T) {
+	viewBox := Rect{Point{10, 20}, Point{110, 70}}
+
+	tests := []struct {
+		description         string
+		preserveAspectRatio string
+		expected            Matrix
+	}{
+		{
+			description: "default",
+			expected:    Matrix{2, 0, 0, 2, -20, 10},
+		},
+		{
+			description:         "none",
+			preserveAspectRatio: "none",
+			expected:            Matrix{2, 0, 0, 4, -20, -80},
+		},
+		{
+			description:         "minimum alignment",
+			preserveAspectRatio: "xMinYMin meet",
+			expected:            Matrix{2, 0, 0, 2, -20, -40},
+		},
+		{
+			description:         "maximum alignment",
+			preserveAspectRatio: "defer xMaxYMax",
+			expected:            Matrix{2, 0, 0, 2, -20, 60},
+		},
+		{
+			description:         "slice",
+			preserveAspectRatio: "xMidYMid slice",
+			expected:            Matrix{4, 0, 0, 4, -140, -80},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			actual, err := ViewBoxTransform(viewBox,
+				test.preserveAspectRatio, 200, 200)
+			if err != nil {
+				t.Fatalf("ViewBox: unexpected error: %s", err)
+			}
+
+			if !matricesClose(actual, test.expected) {
+				t.Errorf("ViewBox: expected %v, actual %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseViewBox(t *testing.T) {
+	actual, err := ParseViewBox(" -10,5 20\t30 ")
+	if err != nil {
+		t.Fatalf("ViewBox: unexpected error: %s", err)
+	}
+
+	if expected := (Rect{Point{-10, 5}, Point{10, 35}}); actual != expected {
+		t.Errorf("ViewBox: expected %v, actual %v", expected, actual)
+	}
+
+	for _, raw := range []string{"", "0 0 10", "0 0 a 10", "0 0 0 10"} {
+		if _, err := ParseViewBox(raw); err == nil {
+			t.Errorf("ViewBox: expected error for %q", raw)
+		}
+	}
+}